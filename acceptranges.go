@@ -0,0 +1,71 @@
+package httpseek
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrRangeNotSupported is returned by Seek to an offset that needs a range request once the server
+// is known not to support ranges: it advertised Accept-Ranges: none, or answered a range request with
+// the full content.
+var ErrRangeNotSupported = errors.New("server does not support range requests")
+
+// parseAcceptRanges returns the lowercased range units listed in the Accept-Ranges header,
+// or nil if the header is absent.
+func parseAcceptRanges(header http.Header) []string {
+	values := header.Values("Accept-Ranges")
+	if len(values) == 0 {
+		return nil
+	}
+	units := []string{}
+	for _, value := range values {
+		for _, unit := range strings.Split(value, ",") {
+			unit = strings.ToLower(strings.TrimSpace(unit))
+			if unit != "" {
+				units = append(units, unit)
+			}
+		}
+	}
+	return units
+}
+
+// recordAcceptRanges keeps the units advertised by the first response that carries the header.
+func (s *Seeker) recordAcceptRanges(header http.Header) {
+	if s.acceptRanges != nil {
+		return
+	}
+	s.acceptRanges = parseAcceptRanges(header)
+	if len(s.acceptRanges) == 1 && s.acceptRanges[0] == "none" {
+		s.rangesUnsupported = true
+	}
+}
+
+// checkSeekable fails with ErrRangeNotSupported if the server does not support ranges
+// and reading at offset would need a range request.
+// A seek to the start, to the end or past it, within the read buffer, within a forward skip
+// of the open stream or within WithRangeFallback is still possible.
+func (s *Seeker) checkSeekable(offset int64) error {
+	s.mu.Lock()
+	unsupported, size := s.rangesUnsupported, s.size
+	s.mu.Unlock()
+	switch {
+	case !unsupported,
+		offset == 0,
+		offset == s.position(),
+		s.buffered.covers(offset),
+		size >= 0 && offset >= size,
+		offset <= s.cfg.rangeFallback,
+		s.rc != nil && offset > s.offset && offset-s.offset <= s.cfg.skipThreshold:
+		return nil
+	}
+	return ErrRangeNotSupported
+}
+
+// AcceptRanges returns the range units advertised by the server in the Accept-Ranges header,
+// such as "bytes" or "none", or nil if no response carried the header yet.
+func (s *Seeker) AcceptRanges() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.acceptRanges
+}
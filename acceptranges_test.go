@@ -0,0 +1,106 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseAcceptRanges(t *testing.T) {
+	tests := []struct {
+		values []string
+		want   []string
+	}{
+		{nil, nil},
+		{[]string{""}, []string{}},
+		{[]string{"bytes"}, []string{"bytes"}},
+		{[]string{"none"}, []string{"none"}},
+		{[]string{"Bytes, items"}, []string{"bytes", "items"}},
+		{[]string{"items", " bytes ,"}, []string{"items", "bytes"}},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		for _, v := range tt.values {
+			header.Add("Accept-Ranges", v)
+		}
+		got := parseAcceptRanges(header)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseAcceptRanges(%q) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestAcceptRanges(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if rsc.AcceptRanges() != nil {
+		t.Fatalf("got %q before any request, want nil", rsc.AcceptRanges())
+	}
+	if _, err := io.ReadAll(rsc); err != nil {
+		t.Fatal(err)
+	}
+	if got := rsc.AcceptRanges(); !reflect.DeepEqual(got, []string{"bytes"}) {
+		t.Fatalf("got %q, want %q", got, []string{"bytes"})
+	}
+}
+
+func TestRangeNotSupported(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Accept-Ranges", "none")
+		w.Header().Set("Content-Length", "12")
+		w.Write([]byte("Hello World!"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithSkipThreshold(4))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(8, io.SeekStart); !errors.Is(err, ErrRangeNotSupported) {
+		t.Fatalf("got %v, want %v", err, ErrRangeNotSupported)
+	}
+	if rsc.Offset() != 2 {
+		t.Fatalf("got offset %d after the failed seek, want 2", rsc.Offset())
+	}
+	for _, offset := range []int64{4, 0, 12} {
+		if _, err := rsc.Seek(offset, io.SeekStart); err != nil {
+			t.Fatalf("Seek(%d): %v", offset, err)
+		}
+	}
+	if _, err := rsc.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+}
@@ -0,0 +1,65 @@
+package httpseek
+
+const maxAttemptHistory = 32
+
+// AttemptInfo describes a request issued by the Seeker.
+type AttemptInfo struct {
+	// Attempt is the number of the request, starting at 1.
+	Attempt int
+	// Offset is the offset at which the requested stream starts.
+	Offset int64
+	// Ranged reports whether the request was sent with a Range header, that is, whether it resumed the transfer.
+	Ranged bool
+	// Level is the resume level the request was sent with.
+	Level ResumeLevel
+	// StatusCode is the HTTP status code of the response, or 0 if none was received.
+	StatusCode int
+	// Err is the error that ended the attempt, or nil.
+	Err error
+	// Hops are the responses received for the attempt, in order, including redirects that were followed.
+	Hops []Hop
+	// Timing is the timing breakdown of the request.
+	Timing AttemptTiming
+}
+
+// CurrentAttempt returns the attempt that produced the stream currently being read,
+// or the zero AttemptInfo if no stream has been opened yet.
+func (s *Seeker) CurrentAttempt() AttemptInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.attempts) - 1; i >= 0; i-- {
+		if s.attempts[i].Attempt == s.currentAttempt {
+			return s.attempts[i]
+		}
+	}
+	return AttemptInfo{}
+}
+
+// Attempts returns the most recent attempts, oldest first.
+func (s *Seeker) Attempts() []AttemptInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]AttemptInfo(nil), s.attempts...)
+}
+
+func (s *Seeker) recordAttempt(info AttemptInfo) {
+	if len(s.attempts) == maxAttemptHistory {
+		copy(s.attempts, s.attempts[1:])
+		s.attempts = s.attempts[:len(s.attempts)-1]
+	}
+	s.attempts = append(s.attempts, info)
+}
+
+// failAttempt records err as the error that ended the given attempt.
+func (s *Seeker) failAttempt(attempt int, err error) {
+	for i := len(s.attempts) - 1; i >= 0; i-- {
+		if s.attempts[i].Attempt == attempt {
+			s.attempts[i].Err = err
+			return
+		}
+	}
+}
+
+type attemptReporter interface {
+	CurrentAttempt() AttemptInfo
+}
@@ -0,0 +1,56 @@
+package httpseek
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCurrentAttempt(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := rand.Intn(3) + 1
+		if r.Header.Get("Range") == "" {
+			n = 5
+		}
+		http.ServeContent(&errorResponseWriter{rw: w, n: n}, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	s.Client().Transport = NewMustReaderTransport(s.Client().Transport, func(r *http.Request, retry int, err error) error {
+		return nil
+	})
+
+	resp, err := s.Client().Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	reporter, ok := resp.Body.(interface{ CurrentAttempt() AttemptInfo })
+	if !ok {
+		t.Fatal("body does not report the current attempt")
+	}
+
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(resp.Body, buf); err != nil {
+		t.Fatal(err)
+	}
+	if got := reporter.CurrentAttempt(); got.Attempt != 1 || got.Ranged || got.Offset != 0 {
+		t.Fatalf("got %+v, want the first unranged attempt", got)
+	}
+
+	rest, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf)+string(rest) != "Hello World!" {
+		t.Fatalf("got %q, want %q", string(buf)+string(rest), "Hello World!")
+	}
+	if got := reporter.CurrentAttempt(); got.Attempt < 2 || !got.Ranged || got.Offset < 5 {
+		t.Fatalf("got %+v, want a resumed attempt", got)
+	}
+}
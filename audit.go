@@ -0,0 +1,161 @@
+package httpseek
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const auditQueueSize = 256
+
+// auditRecord is one line of the audit log.
+type auditRecord struct {
+	// Type is "segment" for a response segment or "summary" for the terminal record written by Close.
+	Type           string    `json:"type"`
+	URL            string    `json:"url"`
+	ETag           string    `json:"etag,omitempty"`
+	LastModified   string    `json:"last_modified,omitempty"`
+	RequestedRange string    `json:"requested_range,omitempty"`
+	Start          int64     `json:"start"`
+	End            int64     `json:"end"`
+	Bytes          int64     `json:"bytes"`
+	StartTime      time.Time `json:"start_time"`
+	EndTime        time.Time `json:"end_time"`
+	Attempt        int       `json:"attempt,omitempty"`
+	Outcome        string    `json:"outcome"`
+	Error          string    `json:"error,omitempty"`
+	Size           int64     `json:"size,omitempty"`
+	Requests       int       `json:"requests,omitempty"`
+	Retries        int       `json:"retries,omitempty"`
+	Dropped        int64     `json:"dropped,omitempty"`
+}
+
+// auditLog writes records from a background goroutine so a slow writer never blocks reading;
+// records that do not fit in the queue are dropped and counted.
+type auditLog struct {
+	records chan auditRecord
+	dropped atomic.Int64
+	done    chan struct{}
+	once    sync.Once
+}
+
+func newAuditLog(w io.Writer) *auditLog {
+	l := &auditLog{
+		records: make(chan auditRecord, auditQueueSize),
+		done:    make(chan struct{}),
+	}
+	go l.run(w)
+	return l
+}
+
+func (l *auditLog) run(w io.Writer) {
+	defer close(l.done)
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	for record := range l.records {
+		_ = enc.Encode(record)
+		if len(l.records) == 0 {
+			_ = bw.Flush()
+		}
+	}
+	_ = bw.Flush()
+}
+
+func (l *auditLog) write(record auditRecord) {
+	select {
+	case l.records <- record:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// close writes the terminal record and waits for the queue to be flushed.
+func (l *auditLog) close(summary auditRecord) {
+	l.once.Do(func() {
+		summary.Dropped = l.dropped.Load()
+		l.records <- summary
+		close(l.records)
+		<-l.done
+	})
+}
+
+// segment describes the response body currently being read, for the audit log.
+type segment struct {
+	record auditRecord
+	active bool
+}
+
+// beginSegment starts auditing the stream just opened at offset.
+func (s *Seeker) beginSegment(offset int64) {
+	if s.audit == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.segment = segment{
+		active: true,
+		record: auditRecord{
+			Type:           "segment",
+			URL:            s.lastRequest.URL.Redacted(),
+			ETag:           s.etag,
+			LastModified:   s.lastModified,
+			RequestedRange: s.lastRequest.Header.Get("Range"),
+			Start:          offset,
+			StartTime:      time.Now(),
+			Attempt:        s.currentAttempt,
+			Outcome:        "closed",
+		},
+	}
+}
+
+// endSegment records how the audited stream ended; the record is written when the body is closed.
+func (s *Seeker) endSegment(outcome string, err error) {
+	if !s.segment.active {
+		return
+	}
+	s.segment.record.Outcome = outcome
+	if err != nil {
+		s.segment.record.Error = err.Error()
+	}
+}
+
+// flushSegment writes the record of the audited stream once its body is closed.
+func (s *Seeker) flushSegment() {
+	if !s.segment.active {
+		return
+	}
+	record := s.segment.record
+	record.End = s.offset
+	record.Bytes = record.End - record.Start
+	record.EndTime = time.Now()
+	s.segment = segment{}
+	s.audit.write(record)
+}
+
+// closeAudit writes the terminal summary record.
+func (s *Seeker) closeAudit() {
+	if s.audit == nil {
+		return
+	}
+	s.mu.Lock()
+	summary := auditRecord{
+		Type:         "summary",
+		ETag:         s.etag,
+		LastModified: s.lastModified,
+		Bytes:        s.stats.BytesRead,
+		EndTime:      time.Now(),
+		StartTime:    s.transferStart,
+		Outcome:      "closed",
+		Size:         s.size,
+		Requests:     s.stats.Requests,
+		Retries:      s.stats.Retries,
+	}
+	if s.lastRequest != nil {
+		summary.URL = s.lastRequest.URL.Redacted()
+	}
+	s.mu.Unlock()
+	s.audit.close(summary)
+}
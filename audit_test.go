@@ -0,0 +1,66 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	var log bytes.Buffer
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithAuditLog(&log))
+
+	if _, err := io.ReadFull(rsc, make([]byte, 5)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(rsc); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var records []auditRecord
+	dec := json.NewDecoder(&log)
+	for dec.More() {
+		var record auditRecord
+		if err := dec.Decode(&record); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, record)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want %d", len(records), 3)
+	}
+	first, second, summary := records[0], records[1], records[2]
+	if first.Type != "segment" || first.Start != 0 || first.End != 5 || first.Outcome != "closed" || first.ETag != `"v1"` {
+		t.Errorf("got first segment %+v", first)
+	}
+	if second.RequestedRange != "bytes=6-" || second.Start != 6 || second.End != 12 || second.Bytes != 6 || second.Outcome != "eof" || second.Attempt != 2 {
+		t.Errorf("got second segment %+v", second)
+	}
+	if summary.Type != "summary" || summary.Bytes != 11 || summary.Size != 12 || summary.Requests != 2 || summary.URL != s.URL {
+		t.Errorf("got summary %+v", summary)
+	}
+}
@@ -0,0 +1,123 @@
+package httpseek
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+type backfillReaderAt struct {
+	local   *os.File
+	extents *ExtentSet
+	remote  *Seeker
+
+	once     sync.Once
+	checkErr error
+
+	mut      sync.Mutex
+	noWrites bool
+}
+
+// NewBackfillReaderAt returns an io.ReaderAt that reads the ranges recorded in extents from local
+// and the others from remote. When local is open for writing, the bytes fetched from remote
+// are written to it and added to extents, which can be persisted as JSON for the next run.
+//
+// Before the first read, the validators of the remote content are compared with the ones recorded
+// in extents, so that a stale local file is never mixed with a newer remote version; a mismatch
+// fails with a ContentChangedError. An ExtentSet with an unknown version adopts the remote one.
+// The returned ReaderAt is safe for concurrent use.
+func NewBackfillReaderAt(local *os.File, extents *ExtentSet, remote *Seeker) io.ReaderAt {
+	return &backfillReaderAt{
+		local:   local,
+		extents: extents,
+		remote:  remote,
+	}
+}
+
+func (b *backfillReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	b.once.Do(func() {
+		b.checkErr = b.check()
+	})
+	if b.checkErr != nil {
+		return 0, b.checkErr
+	}
+
+	size := b.remote.Size()
+	want := p
+	if size >= 0 {
+		if off >= size {
+			return 0, io.EOF
+		}
+		if rest := size - off; int64(len(want)) > rest {
+			want = want[:rest]
+		}
+	}
+
+	end := off + int64(len(want))
+	pos := off
+	for _, gap := range b.extents.Missing(off, end) {
+		if gap.Start > pos {
+			if _, err := b.local.ReadAt(want[pos-off:gap.Start-off], pos); err != nil {
+				return int(pos - off), err
+			}
+		}
+		buf := want[gap.Start-off : gap.End-off]
+		n, err := b.remote.ReadAt(buf, gap.Start)
+		b.backfill(buf[:n], gap.Start)
+		if err != nil {
+			return int(gap.Start-off) + n, err
+		}
+		pos = gap.End
+	}
+	if pos < end {
+		if _, err := b.local.ReadAt(want[pos-off:], pos); err != nil {
+			return int(pos - off), err
+		}
+	}
+	if len(want) < len(p) {
+		return len(want), io.EOF
+	}
+	return len(want), nil
+}
+
+// check compares the version of the remote content with the one the extents were taken from.
+func (b *backfillReaderAt) check() error {
+	remote := b.remote.Validator()
+	if remote.IsZero() {
+		_, err := b.remote.ReadAt(make([]byte, 1), 0)
+		if err != nil && err != io.EOF {
+			return err
+		}
+		remote = b.remote.Validator()
+	}
+	local := b.extents.Validator()
+	if local.IsZero() {
+		b.extents.SetValidator(remote)
+		return nil
+	}
+	if field, oldValue, newValue, ok := local.mismatch(remote); ok {
+		return &ContentChangedError{Field: field, Old: oldValue, New: newValue}
+	}
+	return nil
+}
+
+// backfill writes the bytes fetched at off to the local file and records them,
+// unless the file turned out not to be writable.
+func (b *backfillReaderAt) backfill(p []byte, off int64) {
+	if len(p) == 0 {
+		return
+	}
+	b.mut.Lock()
+	noWrites := b.noWrites
+	b.mut.Unlock()
+	if noWrites {
+		return
+	}
+	if _, err := b.local.WriteAt(p, off); err != nil {
+		b.mut.Lock()
+		b.noWrites = true
+		b.mut.Unlock()
+		return
+	}
+	b.extents.Add(off, off+int64(len(p)))
+}
@@ -0,0 +1,122 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackfillReaderAt(t *testing.T) {
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("0123456789"), 50)
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	newRemote := func() *Seeker {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewSeeker(ctx, s.Client().Transport, req)
+	}
+
+	path := filepath.Join(t.TempDir(), "data")
+	err := os.WriteFile(path, content[:100], 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var extents ExtentSet
+	err = json.Unmarshal([]byte(`{"validator":{"etag":"\"v1\"","size":500},"ranges":[{"start":0,"end":100}]}`), &extents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	local, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Close()
+
+	r := NewBackfillReaderAt(local, &extents, newRemote())
+	buf := make([]byte, 250)
+	if _, err := r.ReadAt(buf, 50); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, content[50:300]) {
+		t.Fatalf("got %q, want %q", buf, content[50:300])
+	}
+	if want := []Range{{Start: 0, End: 300}}; !reflect.DeepEqual(extents.Ranges(), want) {
+		t.Fatalf("got extents %v, want %v", extents.Ranges(), want)
+	}
+
+	before := requests.Load()
+	if _, err := r.ReadAt(buf, 10); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, content[10:260]) {
+		t.Fatalf("got %q, want %q", buf, content[10:260])
+	}
+	if got := requests.Load() - before; got != 0 {
+		t.Fatalf("got %d requests for backfilled data, want none", got)
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content[:300]) {
+		t.Fatal("local file was not backfilled")
+	}
+
+	t.Run("stale", func(t *testing.T) {
+		var stale ExtentSet
+		err := json.Unmarshal([]byte(`{"validator":{"etag":"\"v0\"","size":500},"ranges":[{"start":0,"end":100}]}`), &stale)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = NewBackfillReaderAt(local, &stale, newRemote()).ReadAt(buf, 0)
+		var changedErr *ContentChangedError
+		if !errors.Is(err, ErrContentChanged) || !errors.As(err, &changedErr) || changedErr.Field != "ETag" {
+			t.Fatalf("got %v, want an ETag change", err)
+		}
+	})
+
+	t.Run("read only", func(t *testing.T) {
+		readOnly, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer readOnly.Close()
+
+		extents := NewExtentSet()
+		r := NewBackfillReaderAt(readOnly, extents, newRemote())
+		if _, err := r.ReadAt(buf, 200); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, content[200:450]) {
+			t.Fatalf("got %q, want %q", buf, content[200:450])
+		}
+		if len(extents.Ranges()) != 0 {
+			t.Fatalf("got extents %v for a read-only file, want none", extents.Ranges())
+		}
+		if v := extents.Validator(); v.ETag != `"v1"` || v.Size != 500 {
+			t.Fatalf("got validator %+v, want the remote one", v)
+		}
+	})
+}
@@ -0,0 +1,181 @@
+package httpseek
+
+import (
+	"io"
+	"sync"
+)
+
+// maxCachedBlocks is the number of most recently used blocks kept by WithBlockAlignment.
+const maxCachedBlocks = 8
+
+// blockCache keeps the most recently used aligned blocks fetched by ReadAt, taking their memory from the budget.
+// It is safe for concurrent use.
+type blockCache struct {
+	budget *MemoryBudget
+	// maxBytes, if positive, bounds the bytes of the cached blocks instead of their number, as set by WithCache.
+	maxBytes int64
+
+	mu sync.Mutex
+	// blocks are the cached blocks by index, and order their indexes, least recently used first.
+	blocks map[int64][]byte
+	order  []int64
+	used   int64
+	// etag and size are those of the version of the content the blocks were cut from, "" and -1 if unknown.
+	etag    string
+	size    int64
+	version bool
+}
+
+// get returns the block at index, or nil if it is not cached.
+func (c *blockCache) get(index int64) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.blocks[index]
+	if ok {
+		c.touch(index)
+	}
+	return b
+}
+
+// has reports whether the block at index is cached.
+func (c *blockCache) has(index int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.blocks[index]
+	return ok
+}
+
+// put caches a copy of b as the block at index, evicting the least recently used block if the cache is full,
+// and reports whether the budget granted its memory.
+func (c *blockCache) put(index int64, b []byte) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.blocks[index]; ok {
+		c.touch(index)
+		return true
+	}
+	if c.maxBytes > 0 {
+		if int64(len(b)) > c.maxBytes {
+			return false
+		}
+		for c.used+int64(len(b)) > c.maxBytes {
+			c.evict()
+		}
+	} else if len(c.order) == maxCachedBlocks {
+		c.evict()
+	}
+	if granted := c.budget.acquire(BufferCache, int64(len(b))); granted < int64(len(b)) {
+		c.budget.release(granted)
+		return false
+	}
+	if c.blocks == nil {
+		c.blocks = map[int64][]byte{}
+	}
+	c.blocks[index] = append([]byte(nil), b...)
+	c.order = append(c.order, index)
+	c.used += int64(len(b))
+	return true
+}
+
+// touch marks the block at index as the most recently used. It must be called with c.mu held.
+func (c *blockCache) touch(index int64) {
+	for i, o := range c.order {
+		if o == index {
+			copy(c.order[i:], c.order[i+1:])
+			c.order[len(c.order)-1] = index
+			return
+		}
+	}
+}
+
+// evict drops the least recently used block. It must be called with c.mu held.
+func (c *blockCache) evict() {
+	index := c.order[0]
+	c.order = c.order[:copy(c.order, c.order[1:])]
+	c.used -= int64(len(c.blocks[index]))
+	c.budget.release(int64(len(c.blocks[index])))
+	delete(c.blocks, index)
+}
+
+// close drops every block, giving their memory back to the budget.
+func (c *blockCache) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.order) > 0 {
+		c.evict()
+	}
+}
+
+// readAtBlocks serves ReadAt from aligned blocks, as configured by WithBlockAlignment:
+// cached blocks are copied, and each run of missing blocks is fetched with a single aligned request
+// whose blocks are then cached. Bytes fetched but neither delivered nor cached count as WastedBytes.
+// A block shorter than the block size is the last one of the content.
+func (s *Seeker) readAtBlocks(p []byte, off int64) (int, error) {
+	bs := s.cfg.blockSize
+	end := off + int64(len(p))
+	var n int
+	// A cached block found past a run of missing ones is kept for the next iteration,
+	// so that a Cache such as DiskCache reads it only once.
+	var next []byte
+	var nextIndex int64
+	for pos := off; pos < end; {
+		index := pos / bs
+		b := next
+		if b == nil || nextIndex != index {
+			b = s.getChunk(index)
+		}
+		next = nil
+		if b != nil {
+			s.mu.Lock()
+			s.stats.BlockCacheHits++
+			s.mu.Unlock()
+		} else {
+			last := index
+			for (last+1)*bs < end {
+				if next = s.getChunk(last + 1); next != nil {
+					nextIndex = last + 1
+					break
+				}
+				last++
+			}
+			buf := make([]byte, (last-index+1)*bs)
+			got, err := s.fetch(s.ctx, buf, index*bs)
+			if err != nil && err != io.EOF {
+				return n, err
+			}
+			var cached int
+			for i := 0; i < got; i += int(bs) {
+				block := buf[i:min(i+int(bs), got)]
+				// A short block is only complete at the end of the content.
+				if (len(block) == int(bs) || err == io.EOF) && s.putChunk(index+int64(i)/bs, block) {
+					cached += len(block)
+				}
+			}
+			start := int(pos - index*bs)
+			delivered := copy(p[n:], buf[min(start, got):got])
+			if wasted := got - cached - delivered; wasted > 0 {
+				s.mu.Lock()
+				s.stats.WastedBytes += int64(wasted)
+				s.mu.Unlock()
+			}
+			n += delivered
+			pos += int64(delivered)
+			if pos < end && err == io.EOF {
+				return n, io.EOF
+			}
+			continue
+		}
+		start := pos - index*bs
+		if start >= int64(len(b)) {
+			return n, io.EOF
+		}
+		copied := copy(p[n:], b[start:])
+		s.cacheHit(copied)
+		n += copied
+		pos += int64(copied)
+		if pos < end && int64(len(b)) < bs {
+			return n, io.EOF
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,82 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockAlignment(t *testing.T) {
+	ctx := context.Background()
+
+	const blockSize = 4
+	content := []byte("Hello World! Hello Blocks!")
+	var mu sync.Mutex
+	var ranges []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mu.Unlock()
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	budget := NewMemoryBudget(1 << 10)
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithBlockAlignment(blockSize), WithMemoryBudget(budget))
+
+	for _, read := range []struct {
+		off, n int
+	}{
+		{off: 5, n: 3},
+		{off: 6, n: 2},
+		{off: 3, n: 6},
+		{off: 22, n: 10},
+	} {
+		buf := make([]byte, read.n)
+		n, err := rsc.ReadAt(buf, int64(read.off))
+		want := content[read.off:min(read.off+read.n, len(content))]
+		if n < read.n && err != io.EOF || n == read.n && err != nil {
+			t.Fatalf("ReadAt(%d, %d): got error %v", read.off, read.n, err)
+		}
+		if !bytes.Equal(buf[:n], want) {
+			t.Fatalf("ReadAt(%d, %d): got %q, want %q", read.off, read.n, buf[:n], want)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, r := range ranges {
+		var start, end int
+		if _, err := fmt.Sscanf(r, "bytes=%d-%d", &start, &end); err != nil {
+			t.Fatalf("got range %q, want a bounded range", r)
+		}
+		if start%blockSize != 0 || (end+1)%blockSize != 0 {
+			t.Fatalf("got range %q, want it aligned to %d", r, blockSize)
+		}
+	}
+	// 4-7, then 0-3 and 8-11 around the cached block of the read at 3, then 20-31 past the end.
+	if len(ranges) != 4 {
+		t.Fatalf("got ranges %q, want 4", ranges)
+	}
+	stats := rsc.Stats()
+	if stats.BlockCacheHits != 2 {
+		t.Fatalf("got %d block cache hits, want %d", stats.BlockCacheHits, 2)
+	}
+	if budget.Used() == 0 {
+		t.Fatal("expected the cached blocks to take memory from the budget")
+	}
+	rsc.Close()
+	if used := budget.Used(); used != 0 {
+		t.Fatalf("got %d bytes of the budget used after Close, want 0", used)
+	}
+}
@@ -0,0 +1,101 @@
+package httpseek
+
+import (
+	"sync"
+)
+
+// BufferClass identifies what a buffer is used for, which decides how early it gives way
+// when a MemoryBudget runs low.
+type BufferClass int
+
+const (
+	// BufferReadAhead is speculative data fetched before it is read, such as the tail prefetch.
+	// It may only grow while the budget is less than half used.
+	BufferReadAhead BufferClass = iota
+	// BufferRewind is data already delivered that is kept to verify or replay it, such as the overlap verification.
+	// It may only grow while the budget is less than three quarters used.
+	BufferRewind
+	// BufferCache is data kept to serve repeated reads. It may use the whole budget.
+	BufferCache
+)
+
+func (c BufferClass) String() string {
+	switch c {
+	case BufferRewind:
+		return "rewind"
+	case BufferCache:
+		return "cache"
+	}
+	return "read-ahead"
+}
+
+// limit returns the share of max a class may grow into.
+func (c BufferClass) limit(max int64) int64 {
+	switch c {
+	case BufferReadAhead:
+		return max / 2
+	case BufferRewind:
+		return max / 4 * 3
+	}
+	return max
+}
+
+// MemoryBudget caps the bytes buffered by any number of Seekers sharing it.
+// When it runs low, buffers are granted less memory than they asked for, read-ahead first,
+// then rewind buffers, then caches; reads never fail because of the budget, they only buffer less.
+// A MemoryBudget is safe for concurrent use.
+type MemoryBudget struct {
+	max int64
+
+	mu   sync.Mutex
+	used int64
+	peak int64
+}
+
+// NewMemoryBudget returns a budget of maxBytes shared by the Seekers it is passed to with WithMemoryBudget.
+func NewMemoryBudget(maxBytes int64) *MemoryBudget {
+	return &MemoryBudget{max: maxBytes}
+}
+
+// Max returns the size of the budget.
+func (b *MemoryBudget) Max() int64 {
+	return b.max
+}
+
+// Used returns the bytes currently held by buffers.
+func (b *MemoryBudget) Used() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Peak returns the most bytes held at once.
+func (b *MemoryBudget) Peak() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.peak
+}
+
+// acquire reserves up to n bytes for a buffer of the given class and returns how many were granted.
+// A nil budget grants everything.
+func (b *MemoryBudget) acquire(class BufferClass, n int64) int64 {
+	if b == nil {
+		return n
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	granted := min(n, max(class.limit(b.max)-b.used, 0))
+	b.used += granted
+	b.peak = max(b.peak, b.used)
+	return granted
+}
+
+// release returns n bytes acquired earlier.
+func (b *MemoryBudget) release(n int64) {
+	if b == nil || n <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= n
+}
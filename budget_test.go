@@ -0,0 +1,101 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemoryBudgetClasses(t *testing.T) {
+	b := NewMemoryBudget(1000)
+
+	if got := b.acquire(BufferReadAhead, 800); got != 500 {
+		t.Fatalf("read-ahead: got %d, want %d", got, 500)
+	}
+	if got := b.acquire(BufferRewind, 800); got != 250 {
+		t.Fatalf("rewind: got %d, want %d", got, 250)
+	}
+	if got := b.acquire(BufferReadAhead, 100); got != 0 {
+		t.Fatalf("read-ahead past its share: got %d, want %d", got, 0)
+	}
+	if got := b.acquire(BufferCache, 800); got != 250 {
+		t.Fatalf("cache: got %d, want %d", got, 250)
+	}
+	if b.Used() != 1000 {
+		t.Fatalf("got %d used, want %d", b.Used(), 1000)
+	}
+	b.release(1000)
+	if b.Used() != 0 || b.Peak() != 1000 {
+		t.Fatalf("got %d used and %d peak, want 0 and 1000", b.Used(), b.Peak())
+	}
+}
+
+func TestMemoryBudgetShared(t *testing.T) {
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("0123456789abcdef"), 4096)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	budget := NewMemoryBudget(64 << 10)
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req,
+				WithMemoryBudget(budget),
+				WithTailPrefetch(8<<10),
+				WithOverlapVerify(4<<10),
+			)
+			defer rsc.Close()
+
+			head := make([]byte, 1000)
+			if _, err := io.ReadFull(rsc, head); err != nil {
+				t.Error(err)
+				return
+			}
+			rsc.Release()
+			rest, err := io.ReadAll(rsc)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !bytes.Equal(append(head, rest...), content) {
+				t.Error("content differs")
+			}
+			if _, err := rsc.Seek(-100, io.SeekEnd); err != nil {
+				t.Error(err)
+				return
+			}
+			tail, err := io.ReadAll(rsc)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !bytes.Equal(tail, content[len(content)-100:]) {
+				t.Error("tail differs")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := budget.Peak(); peak > budget.Max() {
+		t.Fatalf("got peak %d, want at most %d", peak, budget.Max())
+	}
+	if used := budget.Used(); used != 0 {
+		t.Fatalf("got %d bytes still used after Close, want none", used)
+	}
+}
@@ -0,0 +1,173 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// readBuffer holds the bytes read from the stream ahead of the caller, such as those returned by Peek.
+// While it holds bytes, it owns the logical offset: the stream is at start+len(buf), the end of the
+// buffer, and the caller is at start+pos. Delivered bytes are kept until the buffer is drained,
+// so a Seek back into them is served without a request.
+type readBuffer struct {
+	start int64
+	buf   []byte
+	pos   int
+}
+
+// pending returns the number of buffered bytes not yet delivered.
+func (b *readBuffer) pending() int {
+	return len(b.buf) - b.pos
+}
+
+// covers reports whether the buffer can serve reads from offset.
+func (b *readBuffer) covers(offset int64) bool {
+	return len(b.buf) > 0 && offset >= b.start && offset <= b.start+int64(len(b.buf))
+}
+
+// clear drops the buffered bytes, keeping the allocation.
+func (b *readBuffer) clear() {
+	b.buf = b.buf[:0]
+	b.pos = 0
+}
+
+// position returns the offset of the next byte delivered to the caller.
+func (s *Seeker) position() int64 {
+	if len(s.buffered.buf) > 0 {
+		return s.buffered.start + int64(s.buffered.pos)
+	}
+	return s.offset
+}
+
+// readBuffered reads from the buffer, or from the stream once the buffer is drained.
+func (s *Seeker) readBuffered(ctx context.Context, p []byte) (int, error) {
+	b := &s.buffered
+	if b.pending() > 0 {
+		n := copy(p, b.buf[b.pos:])
+		b.pos += n
+		return n, nil
+	}
+	b.clear()
+	return s.read(ctx, p)
+}
+
+// seekBuffered moves the logical offset to offset, within the buffer if it covers it.
+func (s *Seeker) seekBuffered(offset int64) error {
+	b := &s.buffered
+	if b.covers(offset) {
+		b.pos = int(offset - b.start)
+		return nil
+	}
+	b.clear()
+	return s.seekTo(offset)
+}
+
+// Peek returns the next n bytes without advancing the offset, reading them from the content as needed.
+// If Peek returns fewer than n bytes, it also returns the error that stopped it, io.EOF at the end of the content.
+// The bytes are valid until the next Read, Peek, Discard or Seek. A Seek back into peeked bytes
+// that were since read is served from the buffer until it is drained or grown by a larger Peek.
+func (s *Seeker) Peek(n int) ([]byte, error) {
+	if n < 0 {
+		return nil, errors.New("negative count")
+	}
+	if s.closed.Load() {
+		return nil, s.closedError("read")
+	}
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	defer s.flushEvents()
+	ctx, ok := s.beginRead()
+	if !ok {
+		return nil, ErrInterrupted
+	}
+
+	b := &s.buffered
+	if b.pending() == 0 {
+		b.clear()
+		b.start = s.offset
+	}
+	if cap(b.buf) < b.pos+n {
+		// Growing drops the delivered bytes.
+		buf := make([]byte, b.pending(), n)
+		copy(buf, b.buf[b.pos:])
+		b.start += int64(b.pos)
+		b.buf, b.pos = buf, 0
+	}
+	var err error
+	for b.pending() < n && err == nil {
+		var m int
+		m, err = s.read(ctx, b.buf[len(b.buf):b.pos+n])
+		b.buf = b.buf[:len(b.buf)+m]
+	}
+	if s.endRead() {
+		_ = s.reset()
+		if err == nil || err == io.EOF {
+			err = ErrInterrupted
+		}
+	}
+	if b.pending() >= n {
+		return b.buf[b.pos : b.pos+n], nil
+	}
+	return b.buf[b.pos:], err
+}
+
+// Discard skips the next n bytes and returns the number of bytes skipped.
+// If Discard skips fewer than n bytes, it also returns an error, io.EOF at the end of the content.
+// When the size is known, bytes past the buffer are skipped by moving the offset without reading them.
+func (s *Seeker) Discard(n int) (int, error) {
+	if n < 0 {
+		return 0, errors.New("negative count")
+	}
+	if s.closed.Load() {
+		return 0, s.closedError("read")
+	}
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	defer s.flushEvents()
+
+	b := &s.buffered
+	discarded := min(n, b.pending())
+	b.pos += discarded
+	if discarded == n {
+		return n, nil
+	}
+	b.clear()
+
+	if size := s.Size(); size >= 0 {
+		target := min(s.offset+int64(n-discarded), max(size, s.offset))
+		discarded += int(target - s.offset)
+		err := s.seekTo(target)
+		if err != nil {
+			return discarded, err
+		}
+		if discarded < n {
+			return discarded, io.EOF
+		}
+		return n, nil
+	}
+
+	ctx, ok := s.beginRead()
+	if !ok {
+		return discarded, ErrInterrupted
+	}
+	bufp := getBuffer(min(n-discarded, copyBufferSize))
+	defer putBuffer(bufp)
+	buf := *bufp
+	var err error
+	for discarded < n && err == nil {
+		var m int
+		m, err = s.read(ctx, buf[:min(len(buf), n-discarded)])
+		discarded += m
+	}
+	if s.endRead() {
+		_ = s.reset()
+		if err == nil || err == io.EOF {
+			err = ErrInterrupted
+		}
+	}
+	if discarded == n {
+		return n, nil
+	}
+	return discarded, err
+}
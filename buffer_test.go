@@ -0,0 +1,166 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestPeek(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := testutil.NewReplayTransport(content)
+	rsc := NewSeekerWithOptions(ctx, transport, req)
+	defer rsc.Close()
+
+	peeked, err := rsc.Peek(5)
+	if err != nil || string(peeked) != "Hello" {
+		t.Fatalf("Peek(5) = %q, %v, want %q", peeked, err, "Hello")
+	}
+	if rsc.Offset() != 0 {
+		t.Fatalf("Offset() = %d after Peek, want 0", rsc.Offset())
+	}
+	buf := make([]byte, 3)
+	if n, err := rsc.Read(buf); err != nil || string(buf[:n]) != "Hel" {
+		t.Fatalf("Read() = %q, %v, want %q", buf[:n], err, "Hel")
+	}
+	if off, err := rsc.Seek(-2, io.SeekCurrent); err != nil || off != 1 {
+		t.Fatalf("Seek(-2, io.SeekCurrent) = %d, %v, want 1", off, err)
+	}
+	if n, err := rsc.Discard(3); err != nil || n != 3 {
+		t.Fatalf("Discard(3) = %d, %v, want 3", n, err)
+	}
+	rest, err := io.ReadAll(rsc)
+	if err != nil || string(rest) != "o World!" {
+		t.Fatalf("ReadAll() = %q, %v, want %q", rest, err, "o World!")
+	}
+	if requests := transport.Requests(); len(requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(requests))
+	}
+
+	if _, err := rsc.Seek(8, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	peeked, err = rsc.Peek(10)
+	if err != io.EOF || string(peeked) != "rld!" {
+		t.Fatalf("Peek(10) at 8 = %q, %v, want %q, io.EOF", peeked, err, "rld!")
+	}
+	if n, err := rsc.Discard(10); err != io.EOF || n != 4 {
+		t.Fatalf("Discard(10) at 8 = %d, %v, want 4, io.EOF", n, err)
+	}
+}
+
+// TestBufferedStress interleaves Peek, Read, Seek and Discard of random sizes
+// and compares every byte with a bytes.Reader over the same content.
+func TestBufferedStress(t *testing.T) {
+	ctx := context.Background()
+	content := make([]byte, 64<<10)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	ops := 1000000
+	if testing.Short() {
+		ops = 50000
+	}
+	configs := []struct {
+		name string
+		opts []Option
+	}{
+		{"default", nil},
+		{"skip", []Option{WithSkipThreshold(4 << 10)}},
+		{"short reads", []Option{WithMaxBytesPerRead(7)}},
+	}
+	for i, cfg := range configs {
+		t.Run(cfg.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(int64(i)))
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, testutil.NewReplayTransport(content), req, cfg.opts...)
+			defer rsc.Close()
+			ref := bytes.NewReader(content)
+
+			size := func() int {
+				if rng.Intn(8) == 0 {
+					return rng.Intn(8 << 10)
+				}
+				return rng.Intn(64)
+			}
+			buf := make([]byte, 8<<10)
+			for op := 0; op < ops; op++ {
+				pos, _ := ref.Seek(0, io.SeekCurrent)
+				if got := rsc.Offset(); got != pos {
+					t.Fatalf("op %d: Offset() = %d, want %d", op, got, pos)
+				}
+				switch rng.Intn(4) {
+				case 0:
+					n := size()
+					peeked, err := rsc.Peek(n)
+					want := make([]byte, n)
+					m, _ := ref.ReadAt(want, pos)
+					if !bytes.Equal(peeked, want[:m]) {
+						t.Fatalf("op %d: Peek(%d) at %d returned %d bytes that differ from the %d expected", op, n, pos, len(peeked), m)
+					}
+					if m < n && err != io.EOF || m == n && err != nil {
+						t.Fatalf("op %d: Peek(%d) at %d: got error %v with %d of %d bytes", op, n, pos, err, m, n)
+					}
+				case 1:
+					p := buf[:size()+1]
+					n, err := rsc.Read(p)
+					if err != nil && !errors.Is(err, io.EOF) {
+						t.Fatalf("op %d: Read: %v", op, err)
+					}
+					want := make([]byte, n)
+					m, _ := ref.Read(want)
+					if m != n || !bytes.Equal(p[:n], want) {
+						t.Fatalf("op %d: Read(%d) at %d returned %d bytes that differ from the content", op, len(p), pos, n)
+					}
+					if n == 0 && err == nil {
+						t.Fatalf("op %d: Read(%d) at %d returned nothing", op, len(p), pos)
+					}
+					if err == io.EOF && pos+int64(n) < int64(len(content)) {
+						t.Fatalf("op %d: Read at %d: early EOF", op, pos)
+					}
+				case 2:
+					var offset int64
+					whence := rng.Intn(3)
+					switch whence {
+					case io.SeekStart:
+						offset = rng.Int63n(int64(len(content)) + 64)
+					case io.SeekCurrent:
+						offset = int64(rng.Intn(257) - 128)
+						if pos+offset < 0 {
+							offset = -pos
+						}
+					case io.SeekEnd:
+						offset = -rng.Int63n(int64(len(content)))
+					}
+					want, _ := ref.Seek(offset, whence)
+					got, err := rsc.Seek(offset, whence)
+					if err != nil || got != want {
+						t.Fatalf("op %d: Seek(%d, %d) = %d, %v, want %d", op, offset, whence, got, err, want)
+					}
+				case 3:
+					n := size()
+					got, err := rsc.Discard(n)
+					want := int(min(int64(n), max(int64(len(content))-pos, 0)))
+					_, _ = ref.Seek(int64(want), io.SeekCurrent)
+					if got != want || want < n && err != io.EOF || want == n && err != nil {
+						t.Fatalf("op %d: Discard(%d) at %d = %d, %v, want %d", op, n, pos, got, err, want)
+					}
+				}
+			}
+		})
+	}
+}
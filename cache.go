@@ -0,0 +1,172 @@
+package httpseek
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Cache stores the chunks of WithChunkCache outside of the Seeker, such as on disk with DiskCache,
+// and may be shared by many Seekers. Its methods may be called from several goroutines at once.
+type Cache interface {
+	// Get returns the chunk at index of the content identified by key, or false if it is not stored.
+	// A chunk shorter than the others of key is the last one of the content.
+	Get(key string, index int64) ([]byte, bool)
+	// Put stores a copy of the chunk at index of the content identified by key,
+	// dropping other chunks as needed to stay within its capacity.
+	Put(key string, index int64, chunk []byte) error
+}
+
+// cacheFill collects the bytes delivered by Read into the chunk at index for WithCache.
+type cacheFill struct {
+	index int64
+	buf   []byte
+}
+
+// observeVersion drops the cached blocks if resp reports another ETag or size than the responses they were cut from.
+func (s *Seeker) observeVersion(resp *http.Response) {
+	if s.cfg.blockSize <= 0 || resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return
+	}
+	size := int64(-1)
+	if resp.StatusCode == http.StatusPartialContent {
+		if _, _, total, err := parseContentRange(resp.Header.Get(contentRangeKey), false); err == nil {
+			size = total
+		}
+	} else if !resp.Uncompressed && contentEncoding(resp.Header) == "" {
+		size = resp.ContentLength
+	}
+	s.blocks.observe(resp.Header.Get("ETag"), size)
+}
+
+// observe records the version of the content reported by a response, dropping every block if it changed.
+// An empty etag or a negative size is unknown and matches any.
+func (c *blockCache) observe(etag string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.version {
+		c.version = true
+		c.etag, c.size = etag, size
+		return
+	}
+	if etag != "" && c.etag != "" && etag != c.etag || size >= 0 && c.size >= 0 && size != c.size {
+		for len(c.order) > 0 {
+			c.evict()
+		}
+		c.etag, c.size = "", -1
+	}
+	if etag != "" {
+		c.etag = etag
+	}
+	if size >= 0 {
+		c.size = size
+	}
+}
+
+// chunkCacheEnabled reports whether Read is served from and feeds the chunks of WithCache or WithChunkCache.
+// A later WithBlockAlignment of 0 leaves no chunk size, and so no cache.
+func (s *Seeker) chunkCacheEnabled() bool {
+	return s.cfg.blockSize > 0 && (s.cfg.cacheMax > 0 || s.cfg.cacheStore != nil)
+}
+
+// cacheKey returns the key of the content in the Cache of WithChunkCache: the URL of the request,
+// the ETag and size of the content and the chunk size. It returns false until a response reported an ETag,
+// without which chunks stored by another Seeker could belong to another version.
+func (s *Seeker) cacheKey() (string, bool) {
+	c := &s.blocks
+	c.mu.Lock()
+	etag, size := c.etag, c.size
+	c.mu.Unlock()
+	if etag == "" {
+		return "", false
+	}
+	return s.req.URL.String() + " " + etag + " " + strconv.FormatInt(size, 10) + " " + strconv.FormatInt(s.cfg.blockSize, 10), true
+}
+
+// getChunk returns the chunk at index from the Cache of WithChunkCache, or else from the blocks kept in memory.
+func (s *Seeker) getChunk(index int64) []byte {
+	if s.cfg.cacheStore == nil {
+		return s.blocks.get(index)
+	}
+	key, ok := s.cacheKey()
+	if !ok {
+		return nil
+	}
+	chunk, _ := s.cfg.cacheStore.Get(key, index)
+	return chunk
+}
+
+// putChunk stores the chunk at index and reports whether it was stored.
+func (s *Seeker) putChunk(index int64, chunk []byte) bool {
+	if s.cfg.cacheStore == nil {
+		return s.blocks.put(index, chunk)
+	}
+	key, ok := s.cacheKey()
+	return ok && s.cfg.cacheStore.Put(key, index, chunk) == nil
+}
+
+// cachedChunk returns the cached chunk of WithCache holding offset and its start, or nil if there is none.
+func (s *Seeker) cachedChunk(offset int64) ([]byte, int64) {
+	if !s.chunkCacheEnabled() {
+		return nil, 0
+	}
+	index := offset / s.cfg.blockSize
+	chunk := s.getChunk(index)
+	if chunk == nil {
+		return nil, 0
+	}
+	s.mu.Lock()
+	s.stats.BlockCacheHits++
+	s.mu.Unlock()
+	return chunk, index * s.cfg.blockSize
+}
+
+// readCached reads from a cached chunk starting at start. A chunk shorter than the chunk size is the last one of the content.
+func (s *Seeker) readCached(chunk []byte, start int64, p []byte) (int, error) {
+	if s.offset >= start+int64(len(chunk)) {
+		return 0, io.EOF
+	}
+	n := copy(p, chunk[s.offset-start:])
+	s.cacheHit(n)
+	return n, nil
+}
+
+// cacheHit reports n bytes served from the cache to the Recorder of WithRecorder, if it is a CacheRecorder.
+func (s *Seeker) cacheHit(n int) {
+	if r, ok := s.cfg.recorder.(CacheRecorder); ok {
+		r.CacheHit(n)
+	}
+}
+
+// feedCache collects the bytes delivered at start into chunks of WithCache, caching each chunk once it is
+// complete, or at the end of the content. Bytes that do not continue the chunk being collected start a new one
+// at the next chunk boundary.
+func (s *Seeker) feedCache(start int64, p []byte, eof bool) {
+	if !s.chunkCacheEnabled() {
+		return
+	}
+	bs := s.cfg.blockSize
+	f := &s.cacheFill
+	if f.buf == nil || start != f.index*bs+int64(len(f.buf)) {
+		skip := (bs - start%bs) % bs
+		if skip > int64(len(p)) {
+			f.buf = nil
+			return
+		}
+		p, start = p[skip:], start+skip
+		f.index, f.buf = start/bs, make([]byte, 0, bs)
+	}
+	for len(p) > 0 {
+		k := min(int(bs)-len(f.buf), len(p))
+		f.buf = append(f.buf, p[:k]...)
+		p = p[k:]
+		if len(f.buf) == int(bs) {
+			s.putChunk(f.index, f.buf)
+			f.index, f.buf = f.index+1, f.buf[:0]
+		}
+	}
+	if eof && len(f.buf) > 0 {
+		s.putChunk(f.index, f.buf)
+		f.buf = nil
+	}
+}
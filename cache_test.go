@@ -0,0 +1,250 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+// cacheTestRecorder is a testRecorder that is also a CacheRecorder.
+type cacheTestRecorder struct {
+	testRecorder
+	hits int
+}
+
+func (r *cacheTestRecorder) CacheHit(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits += n
+}
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	transport := testutil.NewReplayTransport(content)
+	transport.ETag = `"v1"`
+	recorder := &cacheTestRecorder{}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithCache(400, 100), WithRecorder(recorder))
+	defer rsc.Close()
+
+	read := func(off int64, n int) {
+		t.Helper()
+		if _, err := rsc.Seek(off, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]byte, n)
+		if _, err := io.ReadFull(rsc, got); err != nil {
+			t.Fatal(err)
+		}
+		if want := content[off : off+int64(n)]; !bytes.Equal(got, want) {
+			t.Fatalf("got %q at %d, want %q", got, off, want)
+		}
+	}
+	requests := func(want int) {
+		t.Helper()
+		if got := len(transport.Requests()); got != want {
+			t.Fatalf("got %d requests, want %d", got, want)
+		}
+	}
+
+	read(0, 300)
+	requests(1)
+	// The chunks delivered by Read serve the Read after seeking back, and ReadAt.
+	read(50, 250)
+	requests(1)
+	p := make([]byte, 100)
+	if _, err := rsc.ReadAt(p, 150); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p, content[150:250]) {
+		t.Fatalf("got %q, want %q", p, content[150:250])
+	}
+	requests(1)
+	if recorder.hits != 350 {
+		t.Fatalf("got %d bytes of cache hits, want 350", recorder.hits)
+	}
+
+	// Reading to the end keeps the 4 most recently used chunks.
+	read(300, 700)
+	requests(2)
+	read(600, 400)
+	requests(2)
+	if _, err := rsc.Read(p); err != io.EOF {
+		t.Fatalf("got error %v at the end, want io.EOF", err)
+	}
+	requests(2)
+	read(0, 10)
+	requests(3)
+}
+
+func TestCacheInvalidation(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	v1 := testutil.Respond(0, "ETag", `"v1"`)
+	v2 := testutil.Respond(0, "ETag", `"v2"`)
+	transport := testutil.NewReplayTransport(content, v1, v2)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithCache(1000, 100))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 200)); err != nil {
+		t.Fatal(err)
+	}
+	if !rsc.blocks.has(0) || !rsc.blocks.has(1) {
+		t.Fatal("chunks read not cached")
+	}
+	// The ETag of the content changed, so the chunks of the previous version are dropped.
+	_, _ = rsc.ReadAt(make([]byte, 10), 500)
+	if rsc.blocks.has(0) || rsc.blocks.has(1) {
+		t.Fatal("chunks of the previous version still cached")
+	}
+
+	var c blockCache
+	c.observe(`"v1"`, 100)
+	c.put(0, []byte("chunk"))
+	c.observe("", -1)
+	if !c.has(0) {
+		t.Fatal("chunk dropped by a response without a validator")
+	}
+	c.observe(`"v1"`, 200)
+	if c.has(0) {
+		t.Fatal("chunk kept after the size changed")
+	}
+}
+
+func TestCacheChunkSize(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantBlock int64
+	}{
+		{name: "zero", opts: []Option{WithCache(1<<30, 0)}, wantBlock: defaultChunkSize},
+		{name: "negative", opts: []Option{WithCache(1<<30, -1)}, wantBlock: defaultChunkSize},
+		{name: "alignment reset", opts: []Option{WithCache(1000, 100), WithBlockAlignment(0)}, wantBlock: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := testutil.NewReplayTransport(content)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, transport, req, tt.opts...)
+			defer rsc.Close()
+
+			if rsc.cfg.blockSize != tt.wantBlock {
+				t.Fatalf("got chunk size %d, want %d", rsc.cfg.blockSize, tt.wantBlock)
+			}
+			got, err := io.ReadAll(rsc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Fatalf("got %q, want %q", got, content)
+			}
+			p := make([]byte, 10)
+			if _, err := rsc.ReadAt(p, 500); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(p, content[500:510]) {
+				t.Fatalf("got %q, want %q", p, content[500:510])
+			}
+		})
+	}
+}
+
+// countingCache is a Cache in memory counting the calls to Get and Put per chunk index.
+type countingCache struct {
+	mu     sync.Mutex
+	chunks map[int64][]byte
+	gets   map[int64]int
+	puts   map[int64]int
+}
+
+func newCountingCache() *countingCache {
+	return &countingCache{chunks: map[int64][]byte{}, gets: map[int64]int{}, puts: map[int64]int{}}
+}
+
+func (c *countingCache) Get(key string, index int64) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gets[index]++
+	chunk, ok := c.chunks[index]
+	return chunk, ok
+}
+
+func (c *countingCache) Put(key string, index int64, chunk []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.puts[index]++
+	c.chunks[index] = bytes.Clone(chunk)
+	return nil
+}
+
+func TestChunkCacheAccesses(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	transport := testutil.NewReplayTransport(content)
+	transport.ETag = `"v1"`
+	cache := newCountingCache()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithChunkCache(cache, 100))
+	defer rsc.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := rsc.Seek(0, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(rsc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Fatalf("got %q, want %q", got, content)
+		}
+	}
+	// The second pass is served from the chunks without storing them again.
+	for index := int64(0); index < 10; index++ {
+		if cache.puts[index] != 1 {
+			t.Fatalf("chunk %d stored %d times, want once", index, cache.puts[index])
+		}
+	}
+
+	// ReadAt looks up each chunk once, including the cached one ending a run of missing chunks.
+	delete(cache.chunks, 2)
+	delete(cache.chunks, 3)
+	clear(cache.gets)
+	p := make([]byte, 600)
+	if _, err := rsc.ReadAt(p, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p, content[:600]) {
+		t.Fatalf("got %q, want %q", p, content[:600])
+	}
+	for index := int64(0); index < 6; index++ {
+		if cache.gets[index] != 1 {
+			t.Fatalf("chunk %d looked up %d times, want once", index, cache.gets[index])
+		}
+	}
+}
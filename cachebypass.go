@@ -0,0 +1,82 @@
+package httpseek
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ResumeLevel is how strictly resume requests guard against intermediaries serving stale content.
+// It rises with WithCacheBypass each time a resume reveals changed or corrupt content.
+type ResumeLevel int
+
+const (
+	// ResumeNormal sends resume requests as they are.
+	ResumeNormal ResumeLevel = iota
+	// ResumeValidated makes resume requests conditional on the validators of the first response,
+	// with If-Match or If-Unmodified-Since.
+	ResumeValidated
+	// ResumeCacheBypass also adds the cache-bypass directives, such as Cache-Control: no-cache.
+	ResumeCacheBypass
+)
+
+func (l ResumeLevel) String() string {
+	switch l {
+	case ResumeNormal:
+		return "normal"
+	case ResumeValidated:
+		return "validated"
+	case ResumeCacheBypass:
+		return "cache-bypass"
+	}
+	return "unknown"
+}
+
+// defaultCacheBypass are the directives sent by ResumeCacheBypass unless WithCacheBypass sets others.
+var defaultCacheBypass = http.Header{
+	"Cache-Control": {"no-cache"},
+	"Pragma":        {"no-cache"},
+}
+
+// applyResumeLevel adds what the current resume level asks for to a request at a non-zero offset
+// and returns the level applied. It must be called with s.mu held.
+func (s *Seeker) applyResumeLevel(req *http.Request, offset int64) ResumeLevel {
+	if offset == 0 {
+		return ResumeNormal
+	}
+	level := s.resumeLevel
+	if level >= ResumeValidated {
+		if s.etag != "" {
+			req.Header.Set("If-Match", s.etag)
+		} else if s.lastModified != "" {
+			req.Header.Set("If-Unmodified-Since", s.lastModified)
+		}
+	}
+	if level >= ResumeCacheBypass {
+		for key, values := range s.cfg.cacheBypass {
+			req.Header[key] = values
+		}
+	}
+	return level
+}
+
+// escalate raises the resume level after err revealed changed or corrupt content on a resume,
+// and reports whether the resume should be tried again at the new level.
+func (s *Seeker) escalate(err error) bool {
+	if s.cfg.cacheBypass == nil || errors.Is(err, ErrCodeForByteRange) || !isNotResumable(err) {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.resumeLevel == ResumeCacheBypass {
+		return false
+	}
+	s.resumeLevel++
+	return true
+}
+
+// ResumeLevel returns the current resume level.
+func (s *Seeker) ResumeLevel() ResumeLevel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resumeLevel
+}
@@ -0,0 +1,103 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheBypass(t *testing.T) {
+	ctx := context.Background()
+
+	fresh := []byte("Hello World! Hello Seeker!")
+	stale := []byte("Hello Wxrld! Hxllo Sxxkxr!")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A broken cache answers ranged requests with a stale copy unless told not to.
+		content := fresh
+		if r.Header.Get("Range") != "" && r.Header.Get("Cache-Control") != "no-cache" {
+			content = stale
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithOverlapVerify(8), WithCacheBypass(nil))
+	defer rsc.Close()
+
+	head := make([]byte, 12)
+	if _, err := io.ReadFull(rsc, head); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsc.Release(); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := append(head, rest...); !bytes.Equal(got, fresh) {
+		t.Fatalf("got %q, want %q", got, fresh)
+	}
+
+	var levels []ResumeLevel
+	for _, attempt := range rsc.Attempts() {
+		levels = append(levels, attempt.Level)
+	}
+	want := []ResumeLevel{ResumeNormal, ResumeNormal, ResumeValidated, ResumeCacheBypass}
+	if len(levels) != len(want) {
+		t.Fatalf("got levels %v, want %v", levels, want)
+	}
+	for i := range want {
+		if levels[i] != want[i] {
+			t.Fatalf("got levels %v, want %v", levels, want)
+		}
+	}
+	if level := rsc.ResumeLevel(); level != ResumeCacheBypass {
+		t.Fatalf("got level %v, want %v", level, ResumeCacheBypass)
+	}
+}
+
+func TestCacheBypassDisabled(t *testing.T) {
+	ctx := context.Background()
+
+	var bypassed bool
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Cache-Control") != "" || r.Header.Get("If-Match") != "" {
+			bypassed = true
+		}
+		content := []byte("Hello World! Hello Seeker!")
+		if r.Header.Get("Range") != "" {
+			content = []byte("Hello Wxrld! Hxllo Sxxkxr!")
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithOverlapVerify(8))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 12)); err != nil {
+		t.Fatal(err)
+	}
+	rsc.Release()
+	if _, err := io.ReadAll(rsc); err == nil {
+		t.Fatal("got no error, want the overlap mismatch")
+	}
+	if bypassed {
+		t.Fatal("got conditional or cache-bypass headers without WithCacheBypass")
+	}
+}
@@ -0,0 +1,118 @@
+package httpseek
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrContentChanged is matched by errors.Is when the content changed between requests.
+var ErrContentChanged = errors.New("content changed between requests")
+
+// ContentChangedError is returned when a resumed response reveals that the content
+// differs from what the first response described.
+type ContentChangedError struct {
+	// Offset is the offset of the resumed request.
+	Offset int64
+	// Field names what changed, such as "Content-Location".
+	Field string
+	// Old is the value seen on the first response.
+	Old string
+	// New is the value seen on the resumed response.
+	New string
+	// Before is what was known about the version of the content before the resumed response,
+	// and After what that response reported. They are zero when the change was found otherwise.
+	Before Validator
+	After  Validator
+	// Err is the underlying failure, such as a SizeChangedError, or nil.
+	Err error
+}
+
+func (e *ContentChangedError) Error() string {
+	return fmt.Sprintf("%s changed from %q to %q at offset %d", e.Field, e.Old, e.New, e.Offset)
+}
+
+func (e *ContentChangedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ContentChangedError) Is(target error) bool {
+	return target == ErrContentChanged
+}
+
+// changedBy records in err the version known before resp and the one resp reported, with the given total size.
+// It must be called with s.mu held.
+func (s *Seeker) changedBy(err *ContentChangedError, resp *http.Response, size int64) *ContentChangedError {
+	err.Before = Validator{ETag: s.etag, LastModified: s.lastModified, Size: s.size}
+	err.After = Validator{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified"), Size: size}
+	return err
+}
+
+// checkSizeAt is checkSize for a response at offset, reporting a changed size as a changed content.
+// It must be called with s.mu held.
+func (s *Seeker) checkSizeAt(offset int64, resp *http.Response, size int64) error {
+	err := s.checkSize(size)
+	if err == nil {
+		return nil
+	}
+	return s.changedBy(&ContentChangedError{
+		Offset: offset,
+		Field:  "Size",
+		Old:    strconv.FormatInt(s.size, 10),
+		New:    strconv.FormatInt(size, 10),
+		Err:    err,
+	}, resp, size)
+}
+
+// checkRepresentation records the Content-Location and the Vary'd request headers of the first response,
+// and compares the Content-Location of later responses against it.
+func (s *Seeker) checkRepresentation(offset int64, req *http.Request, resp *http.Response) error {
+	contentLocation := resp.Header.Get("Content-Location")
+	if !s.representationKnown {
+		s.representationKnown = true
+		s.contentLocation = contentLocation
+		for _, key := range parseVary(resp.Header) {
+			if s.pinnedHeaders == nil {
+				s.pinnedHeaders = http.Header{}
+			}
+			s.pinnedHeaders[key] = req.Header.Values(key)
+		}
+		return nil
+	}
+	if s.contentLocation == "" || contentLocation == "" || s.contentLocation == contentLocation {
+		return nil
+	}
+	return s.changedBy(&ContentChangedError{
+		Offset: offset,
+		Field:  "Content-Location",
+		Old:    s.contentLocation,
+		New:    contentLocation,
+	}, resp, -1)
+}
+
+// pinHeaders sets the Vary'd request headers to the values sent with the first request,
+// so resumes negotiate the same representation.
+func (s *Seeker) pinHeaders(req *http.Request) {
+	for key, values := range s.pinnedHeaders {
+		if len(values) == 0 {
+			req.Header.Del(key)
+		} else {
+			req.Header[key] = values
+		}
+	}
+}
+
+func parseVary(header http.Header) []string {
+	var keys []string
+	for _, value := range header.Values("Vary") {
+		for _, key := range strings.Split(value, ",") {
+			key = strings.TrimSpace(key)
+			if key != "" && key != "*" {
+				keys = append(keys, http.CanonicalHeaderKey(key))
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,151 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRepresentationPinned(t *testing.T) {
+	ctx := context.Background()
+
+	var languages []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		languages = append(languages, r.Header.Get("Accept-Language"))
+		w.Header().Set("Vary", "Accept-Language")
+		w.Header().Set("Content-Location", "/test."+r.Header.Get("Accept-Language"))
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Language", "en")
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Language", "fr")
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+	if len(languages) != 2 || languages[1] != "en" {
+		t.Fatalf("got Accept-Language %q, want the resume pinned to %q", languages, "en")
+	}
+}
+
+func TestContentLocationChanged(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			w.Header().Set("Content-Location", "/test.gz")
+		} else {
+			w.Header().Set("Content-Location", "/test.br")
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	_, err = rsc.Read(make([]byte, 1))
+	if !errors.Is(err, ErrContentChanged) {
+		t.Fatalf("got %v, want %v", err, ErrContentChanged)
+	}
+	var changedErr *ContentChangedError
+	if !errors.As(err, &changedErr) || changedErr.Field != "Content-Location" || changedErr.New != "/test.br" {
+		t.Fatalf("got %v, want Content-Location change", err)
+	}
+}
+
+func TestIfRange(t *testing.T) {
+	ctx := context.Background()
+
+	var version atomic.Int32
+	var ifRanges []string
+	var mut sync.Mutex
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		ifRanges = append(ifRanges, r.Header.Get("If-Range"))
+		mut.Unlock()
+		content := "Hello World! Hello Seeker!"
+		w.Header().Set("ETag", `"v1"`)
+		if version.Load() != 0 {
+			content = "Goodbye World! Goodbye Seeker!"
+			w.Header().Set("ETag", `"v2"`)
+		}
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 6)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(13, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello Seeker!" {
+		t.Fatalf("got %q, want %q", got, "Hello Seeker!")
+	}
+
+	version.Store(1)
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	_, err = rsc.Read(make([]byte, 1))
+	var changedErr *ContentChangedError
+	if !errors.Is(err, ErrNotResumable) || !errors.As(err, &changedErr) || changedErr.Field != "ETag" || changedErr.New != `"v2"` {
+		t.Fatalf("got %v, want an ETag change", err)
+	}
+	if changedErr.Before.ETag != `"v1"` || changedErr.After.ETag != `"v2"` || changedErr.Before.Size != 26 || changedErr.After.Size != 30 {
+		t.Fatalf("got versions %+v and %+v, want v1 of 26 bytes and v2 of 30 bytes", changedErr.Before, changedErr.After)
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+	if want := []string{"", `"v1"`, `"v1"`}; len(ifRanges) != len(want) || ifRanges[0] != want[0] || ifRanges[1] != want[1] || ifRanges[2] != want[2] {
+		t.Fatalf("got If-Range %q, want %q", ifRanges, want)
+	}
+}
@@ -0,0 +1,119 @@
+package httpseek
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Range is the half-open byte range [Start, End).
+type Range struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// Checkpointer records which byte ranges of a transfer are complete.
+// RestoreTo with WithCheckpointer, and Download with DownloadOptions.Checkpointer, call MarkComplete
+// after a chunk has been written, and verified when per-chunk digests are enabled,
+// and consult Completed at startup to fetch only the missing chunks.
+// Read and WriteTo stream the content without checkpointing.
+type Checkpointer interface {
+	MarkComplete(start, end int64) error
+	Completed() []Range
+}
+
+// FileCheckpointer is a Checkpointer that persists the completed ranges as JSON in a file.
+type FileCheckpointer struct {
+	mut    sync.Mutex
+	path   string
+	ranges []Range
+}
+
+// NewFileCheckpointer returns a FileCheckpointer storing its state at path,
+// loading the ranges recorded by a previous run if the file exists.
+// Use CheckpointPath to place the file next to the output.
+func NewFileCheckpointer(path string) (*FileCheckpointer, error) {
+	c := &FileCheckpointer{
+		path: path,
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return c, nil
+		}
+		return nil, err
+	}
+	err = json.Unmarshal(data, &c.ranges)
+	if err != nil {
+		return nil, err
+	}
+	c.ranges = mergeRanges(c.ranges)
+	return c, nil
+}
+
+// CheckpointPath returns the path of the checkpoint file kept next to output.
+func CheckpointPath(output string) string {
+	return output + ".checkpoint.json"
+}
+
+// MarkComplete records [start, end) as complete and persists the state.
+func (c *FileCheckpointer) MarkComplete(start, end int64) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.ranges = addRange(c.ranges, Range{Start: start, End: end})
+	data, err := json.Marshal(c.ranges)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Completed returns the completed ranges in ascending order.
+func (c *FileCheckpointer) Completed() []Range {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return append([]Range(nil), c.ranges...)
+}
+
+// Remove deletes the checkpoint file, typically once the transfer has finished.
+func (c *FileCheckpointer) Remove() error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.ranges = nil
+	err := os.Remove(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// mergeRanges sorts ranges and merges the ones that overlap or touch.
+func mergeRanges(ranges []Range) []Range {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].Start < ranges[j].Start
+	})
+	merged := ranges[:0]
+	for _, r := range ranges {
+		if r.End <= r.Start {
+			continue
+		}
+		if n := len(merged); n > 0 && r.Start <= merged[n-1].End {
+			if r.End > merged[n-1].End {
+				merged[n-1].End = r.End
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
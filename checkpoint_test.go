@@ -0,0 +1,44 @@
+package httpseek
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestFileCheckpointer(t *testing.T) {
+	path := CheckpointPath(filepath.Join(t.TempDir(), "output"))
+
+	c, err := NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range []Range{{20, 30}, {0, 10}, {10, 15}, {25, 40}} {
+		if err := c.MarkComplete(r.Start, r.End); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []Range{{0, 15}, {20, 40}}
+	if got := c.Completed(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	c, err = NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Completed(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v after reload, want %v", got, want)
+	}
+
+	if err := c.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	c, err = NewFileCheckpointer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Completed(); len(got) != 0 {
+		t.Fatalf("got %v after remove, want none", got)
+	}
+}
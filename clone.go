@@ -0,0 +1,55 @@
+package httpseek
+
+import (
+	"net/http"
+	"slices"
+)
+
+// Clone returns a new Seeker for the same content, with the request template, options and
+// everything s learned about the content, such as the size, the validators and the first response
+// without its body, but its own offset, at 0, and its own body. The clone makes no request until it is read,
+// and its requests are conditional on the validators of s, so a change of the content since is detected.
+//
+// The Seekers are independent afterwards: closing either one does not affect the other.
+// Clone may be called concurrently with Read.
+func (s *Seeker) Clone() *Seeker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := &Seeker{
+		ctx:       s.ctx,
+		transport: s.transport,
+		req:       s.req.Clone(s.ctx),
+		cfg:       s.cfg,
+		size:      s.size,
+
+		contentType:        s.contentType,
+		contentTypeKnown:   s.contentTypeKnown,
+		sniffedContentType: s.sniffedContentType,
+
+		acceptRanges:      slices.Clone(s.acceptRanges),
+		rangesUnsupported: s.rangesUnsupported,
+
+		ifRangeChecked:    s.ifRangeChecked,
+		ifRangeUnreliable: s.ifRangeUnreliable,
+
+		representationKnown: s.representationKnown,
+		contentLocation:     s.contentLocation,
+		pinnedHeaders:       s.pinnedHeaders.Clone(),
+
+		etag:         s.etag,
+		lastModified: s.lastModified,
+		finalURL:     s.finalURL,
+
+		digest: s.digest,
+	}
+	if s.firstResponse != nil {
+		resp := *s.firstResponse
+		resp.Body = http.NoBody
+		c.firstResponse = &resp
+	}
+	if s.fpSum != nil {
+		c.fpLen, c.fpSum = s.fpLen, s.fpSum
+	}
+	c.init()
+	return c
+}
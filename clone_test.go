@@ -0,0 +1,80 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClone(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+
+	var mut sync.Mutex
+	var received []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		received = append(received, r.Method+" "+r.Header.Get("Range")+" "+r.Header.Get("If-Range"))
+		mut.Unlock()
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req)
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(rsc, head); err != nil {
+		t.Fatal(err)
+	}
+
+	clone := rsc.Clone()
+	defer clone.Close()
+	if clone.Offset() != 0 || clone.Size() != int64(len(content)) || clone.Validator().ETag != `"v1"` {
+		t.Fatalf("got offset %d, size %d and validator %+v, want 0, %d and the ETag", clone.Offset(), clone.Size(), clone.Validator(), len(content))
+	}
+	if off, err := clone.Seek(-6, io.SeekEnd); err != nil || off != 6 {
+		t.Fatalf("Seek(-6, io.SeekEnd) = %d, %v, want 6", off, err)
+	}
+	if resp, err := clone.Response(); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("got response %v, %v, want the first response", resp, err)
+	}
+
+	// Closing the original leaves the clone reading.
+	rsc.Close()
+	tail, err := io.ReadAll(clone)
+	if err != nil || string(tail) != "World!" {
+		t.Fatalf("got %q, %v from the clone, want %q", tail, err, "World!")
+	}
+
+	// Closing a clone leaves the clone it was made from reading.
+	again := clone.Clone()
+	again.Close()
+	if _, err := clone.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	all, err := io.ReadAll(clone)
+	if err != nil || string(all) != string(content) {
+		t.Fatalf("got %q, %v from the clone, want %q", all, err, content)
+	}
+
+	want := []string{"GET  ", `GET bytes=6- "v1"`, "GET  "}
+	mut.Lock()
+	defer mut.Unlock()
+	if len(received) != len(want) {
+		t.Fatalf("got requests %q, want %q", received, want)
+	}
+	for i := range want {
+		if received[i] != want[i] {
+			t.Fatalf("got requests %q, want %q", received, want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package httpseek
+
+import (
+	"time"
+)
+
+// Summary describes a transfer that delivered the whole content.
+type Summary struct {
+	// Size is the size of the content.
+	Size int64
+	// Elapsed is the time from the first request to completion.
+	Elapsed time.Duration
+	// Stats are the counters at completion.
+	Stats Stats
+}
+
+// IsComplete reports whether every byte of the content has been delivered by Read,
+// counting only bytes read contiguously from offset 0.
+//
+// With a known size the transfer is complete as soon as the last byte is read,
+// even if the body is closed before Read returns the terminal io.EOF.
+// With an unknown size it is complete only once Read returns a clean io.EOF,
+// which also makes the size known.
+// With WithDigestVerification, content that does not match the digest announced for it is never complete.
+func (s *Seeker) IsComplete() bool {
+	return s.complete
+}
+
+// updateComplete records that the bytes from start to the current offset were delivered,
+// and fires the completion hook once all of them are and match the digest of WithDigestVerification.
+func (s *Seeker) updateComplete(start int64, eof bool) {
+	if s.complete || start > s.delivered {
+		return
+	}
+	s.delivered = max(s.delivered, s.offset)
+	s.mu.Lock()
+	if s.size < 0 {
+		// The end of a section is not the end of the content.
+		if !eof || s.cfg.sectionEnd > 0 {
+			s.mu.Unlock()
+			return
+		}
+		s.setSize(int64(s.delivered))
+	}
+	if int64(s.delivered) < s.size {
+		s.mu.Unlock()
+		return
+	}
+	// Content that does not match its announced digest is not complete.
+	s.digestErr = s.checkDigestLocked()
+	if s.digestErr != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.complete = true
+	summary := Summary{
+		Size:    s.size,
+		Elapsed: time.Since(s.transferStart),
+		Stats:   s.statsLocked(),
+	}
+	s.emitLocked(TransferComplete{Summary: summary})
+	s.mu.Unlock()
+}
+
+type completeReporter interface {
+	IsComplete() bool
+}
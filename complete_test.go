@@ -0,0 +1,112 @@
+package httpseek
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsComplete(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			w.Write([]byte("Hello "))
+			w.(http.Flusher).Flush()
+			w.Write([]byte("World!"))
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	tests := []struct {
+		name string
+		path string
+		read func(t *testing.T, rsc *Seeker)
+		want bool
+	}{
+		{
+			name: "read to eof",
+			read: func(t *testing.T, rsc *Seeker) {
+				if _, err := io.ReadAll(rsc); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: true,
+		},
+		{
+			name: "last byte without eof",
+			read: func(t *testing.T, rsc *Seeker) {
+				if _, err := io.ReadFull(rsc, make([]byte, 12)); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: true,
+		},
+		{
+			name: "partial",
+			read: func(t *testing.T, rsc *Seeker) {
+				if _, err := io.ReadFull(rsc, make([]byte, 11)); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "tail only",
+			read: func(t *testing.T, rsc *Seeker) {
+				if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := io.ReadAll(rsc); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "unknown size to eof",
+			path: "/chunked",
+			read: func(t *testing.T, rsc *Seeker) {
+				if _, err := io.ReadAll(rsc); err != nil {
+					t.Fatal(err)
+				}
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var summaries []Summary
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithOnComplete(func(summary Summary) {
+				summaries = append(summaries, summary)
+			}))
+
+			tt.read(t, rsc)
+			rsc.Close()
+
+			if got := rsc.IsComplete(); got != tt.want {
+				t.Fatalf("got complete %v, want %v", got, tt.want)
+			}
+			if !tt.want {
+				if len(summaries) != 0 {
+					t.Fatalf("got %d completion calls, want none", len(summaries))
+				}
+				return
+			}
+			if len(summaries) != 1 {
+				t.Fatalf("got %d completion calls, want 1", len(summaries))
+			}
+			if summaries[0].Size != 12 {
+				t.Fatalf("got size %d, want %d", summaries[0].Size, 12)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package httpseek
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// ContentTypeCheck selects how the Content-Type of a resumed response is compared with the first response.
+type ContentTypeCheck int
+
+const (
+	// ContentTypeExact requires the media type to match, ignoring parameters such as charset.
+	ContentTypeExact ContentTypeCheck = iota
+	// ContentTypeMajor requires only the major type, such as "text" in "text/html", to match.
+	ContentTypeMajor
+	// ContentTypeIgnore disables the check, for servers that legitimately vary the header.
+	ContentTypeIgnore
+)
+
+// ContentTypeChangedError is returned when a resumed response has a different Content-Type than the first response,
+// which usually means an error page was served in place of the content.
+type ContentTypeChangedError struct {
+	// Offset is the offset of the resumed request.
+	Offset int64
+	// Old is the Content-Type of the first response.
+	Old string
+	// New is the Content-Type of the resumed response.
+	New string
+}
+
+func (e *ContentTypeChangedError) Error() string {
+	return fmt.Sprintf("Content-Type changed from %q to %q at offset %d", e.Old, e.New, e.Offset)
+}
+
+// checkContentType records the Content-Type of the first response and compares later ones against it.
+func (s *Seeker) checkContentType(offset int64, contentType string) error {
+	if !s.contentTypeKnown {
+		s.contentType = contentType
+		s.contentTypeKnown = true
+		return nil
+	}
+	if s.contentType == "" || contentType == "" {
+		return nil
+	}
+	if sameContentType(s.cfg.contentTypeCheck, s.contentType, contentType) {
+		return nil
+	}
+	s.stats.ContentTypeChanges++
+	return &ContentTypeChangedError{
+		Offset: offset,
+		Old:    s.contentType,
+		New:    contentType,
+	}
+}
+
+func sameContentType(check ContentTypeCheck, a, b string) bool {
+	switch check {
+	case ContentTypeIgnore:
+		return true
+	case ContentTypeMajor:
+		a, _, _ = strings.Cut(mediaType(a), "/")
+		b, _, _ = strings.Cut(mediaType(b), "/")
+	default:
+		a = mediaType(a)
+		b = mediaType(b)
+	}
+	return a == b
+}
+
+func mediaType(contentType string) string {
+	typ, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(contentType))
+	}
+	return typ
+}
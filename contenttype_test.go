@@ -0,0 +1,64 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContentTypeChanged(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "text/html")
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	tests := []struct {
+		check   ContentTypeCheck
+		changed bool
+	}{
+		{ContentTypeExact, true},
+		{ContentTypeMajor, false},
+		{ContentTypeIgnore, false},
+	}
+	for _, tt := range tests {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithContentTypeCheck(tt.check))
+
+		if _, err := io.ReadFull(rsc, make([]byte, 1)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		_, err = rsc.Read(make([]byte, 1))
+
+		var changedErr *ContentTypeChangedError
+		if errors.As(err, &changedErr) != tt.changed {
+			t.Fatalf("check %d: got error %v, want changed %v", tt.check, err, tt.changed)
+		}
+		if tt.changed {
+			if changedErr.Old != "text/plain; charset=utf-8" || changedErr.New != "text/html" {
+				t.Fatalf("got %q -> %q", changedErr.Old, changedErr.New)
+			}
+			if rsc.Stats().ContentTypeChanges != 1 {
+				t.Fatalf("got %d content type changes, want %d", rsc.Stats().ContentTypeChanges, 1)
+			}
+		}
+		rsc.Close()
+	}
+}
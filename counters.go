@@ -0,0 +1,39 @@
+package httpseek
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// fetchedBody counts the bytes read from a response body into BytesFetched.
+type fetchedBody struct {
+	io.ReadCloser
+	n *atomic.Int64
+}
+
+func (b *fetchedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n.Add(int64(n))
+	return n, err
+}
+
+// RequestCount returns the number of HTTP requests sent, counting each redirect followed as a request of its own,
+// unlike Stats.Requests. It may be called at any time, including concurrently with Read and after Close.
+func (s *Seeker) RequestCount() int {
+	return int(s.requestCount.Load())
+}
+
+// BytesFetched returns the number of bytes of response bodies received from the network, including those
+// discarded, such as the overlap re-requested to verify a resume or the bytes skipped by a forward Seek.
+// It may be called at any time, including concurrently with Read and after Close.
+func (s *Seeker) BytesFetched() int64 {
+	return s.bytesFetched.Load()
+}
+
+// Retries returns the number of failed requests that were retried, as Stats.Retries.
+// It may be called at any time, including concurrently with Read and after Close.
+func (s *Seeker) Retries() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stats.Retries
+}
@@ -0,0 +1,61 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestCounters(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	transport := testutil.NewReplayTransport(content,
+		testutil.Respond(http.StatusTooManyRequests),
+		testutil.Respond(http.StatusFound, "Location", "http://example.com/moved"),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithRetry(3, time.Millisecond))
+	defer rsc.Close()
+
+	check := func(requests int, fetched int64, retries int) {
+		t.Helper()
+		if got := rsc.RequestCount(); got != requests {
+			t.Errorf("got %d requests, want %d", got, requests)
+		}
+		if got := rsc.BytesFetched(); got != fetched {
+			t.Errorf("got %d bytes fetched, want %d", got, fetched)
+		}
+		if got := rsc.Retries(); got != retries {
+			t.Errorf("got %d retries, want %d", got, retries)
+		}
+	}
+
+	// The 429 is retried, and the retry is redirected.
+	if _, err := io.ReadFull(rsc, make([]byte, 100)); err != nil {
+		t.Fatal(err)
+	}
+	check(3, 100, 1)
+
+	if _, err := rsc.Seek(50, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	check(3, 100, 1)
+	if _, err := io.ReadFull(rsc, make([]byte, 100)); err != nil {
+		t.Fatal(err)
+	}
+	check(4, 200, 1)
+
+	if err := rsc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	check(4, 200, 1)
+}
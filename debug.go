@@ -0,0 +1,117 @@
+package httpseek
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var redactedHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// redactHeader returns a copy of h with the values of redactedHeaders replaced.
+func redactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, key := range redactedHeaders {
+		if h.Get(key) != "" {
+			h.Set(key, "REDACTED")
+		}
+	}
+	return h
+}
+
+// redactURL returns a copy of u without its user information and with the values of its query replaced,
+// since presigned URLs carry their credentials there.
+func redactURL(u *url.URL) *url.URL {
+	redacted := *u
+	redacted.User = nil
+	if u.RawQuery != "" {
+		query := u.Query()
+		for key := range query {
+			query[key] = []string{"REDACTED"}
+		}
+		redacted.RawQuery = query.Encode()
+	}
+	return &redacted
+}
+
+type debugDumper struct {
+	mut       sync.Mutex
+	w         io.Writer
+	bodyBytes int
+}
+
+// dump writes the failed attempt to the writer.
+// If resp is not nil, the part of its body that was dumped is put back so the body can still be read.
+func (d *debugDumper) dump(attempt int, req *http.Request, resp *http.Response, cause error) {
+	if d == nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- attempt %d at %s\n", attempt, time.Now().Format(time.RFC3339Nano))
+
+	req = req.Clone(req.Context())
+	req.URL = redactURL(req.URL)
+	req.Header = redactHeader(req.Header)
+	reqDump, err := httputil.DumpRequest(req, false)
+	if err != nil {
+		fmt.Fprintf(&buf, "dump request: %v\n", err)
+	} else {
+		buf.Write(reqDump)
+	}
+
+	if resp != nil {
+		respDump, err := httputil.DumpResponse(resp, false)
+		if err != nil {
+			fmt.Fprintf(&buf, "dump response: %v\n", err)
+		} else {
+			buf.Write(respDump)
+		}
+		if d.bodyBytes > 0 && resp.Body != nil {
+			body := make([]byte, d.bodyBytes)
+			n, _ := io.ReadFull(resp.Body, body)
+			buf.Write(body[:n])
+			buf.WriteString("\n")
+			resp.Body = struct {
+				io.Reader
+				io.Closer
+			}{
+				Reader: io.MultiReader(bytes.NewReader(body[:n]), resp.Body),
+				Closer: resp.Body,
+			}
+		}
+	}
+
+	if cause != nil {
+		fmt.Fprintf(&buf, "error: %v\n", cause)
+	}
+
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	_, _ = d.w.Write(buf.Bytes())
+}
+
+// dumpStreamLocked dumps the attempt read by Read when its body fails with cause.
+// The body has been read from, so only the headers of the response are dumped. s.mu must be held.
+func (s *Seeker) dumpStreamLocked(cause error) {
+	if s.cfg.debugDump == nil || s.streamRequest == nil {
+		return
+	}
+	var resp *http.Response
+	if s.streamResponse != nil {
+		r := *s.streamResponse
+		r.Body = nil
+		resp = &r
+	}
+	s.cfg.debugDump.dump(s.currentAttempt, s.streamRequest, resp, cause)
+}
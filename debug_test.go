@@ -0,0 +1,102 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestDebugDump(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.Error(w, "object not found", http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	var dump bytes.Buffer
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithDebugDump(&dump, 6))
+	if _, err := io.ReadAll(rsc); err != nil {
+		t.Fatal(err)
+	}
+	rsc.Close()
+	if dump.Len() != 0 {
+		t.Fatalf("got dump for successful attempt: %q", dump.String())
+	}
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/missing", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	rsc = NewSeekerWithOptions(ctx, s.Client().Transport, req, WithDebugDump(&dump, 6))
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "object not found\n" {
+		t.Fatalf("got %q, want %q", got, "object not found\n")
+	}
+
+	out := dump.String()
+	for _, want := range []string{"--- attempt 1 at ", "GET /missing", "Authorization: REDACTED", "404 Not Found", "object"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dump %q does not contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "secret") || strings.Contains(out, "not found\n") {
+		t.Errorf("dump %q leaks credentials or more body than requested", out)
+	}
+}
+
+func TestDebugDumpBrokenBody(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	transport := testutil.NewReplayTransport(content, testutil.ServeThenFail(5, errors.New("connection reset")))
+
+	var dump bytes.Buffer
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file?X-Amz-Credential=key&X-Amz-Signature=sig", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithRetry(0, 0), WithDebugDump(&dump, 6))
+	defer rsc.Close()
+
+	got, err := io.ReadAll(NewMustReader(rsc, nil))
+	if err != nil || string(got) != string(content) {
+		t.Fatalf("got %q, %v, want %q", got, err, content)
+	}
+
+	out := dump.String()
+	for _, want := range []string{"--- attempt 1 at ", "X-Amz-Credential=REDACTED", "X-Amz-Signature=REDACTED", "200 OK", "error: connection reset"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("dump %q does not contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "key") || strings.Contains(out, "sig&") || strings.Contains(out, "=sig") {
+		t.Errorf("dump %q leaks the query credentials", out)
+	}
+	if strings.Count(out, "--- attempt") != 1 {
+		t.Errorf("dump %q holds other attempts than the broken one", out)
+	}
+}
@@ -0,0 +1,124 @@
+package httpseek
+
+import (
+	"bytes"
+	"crypto"
+	_ "crypto/md5"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// announcedDigest is the digest of the content announced by the first response, for WithDigestVerification.
+type announcedDigest struct {
+	// recorded is set once the first response has been looked at.
+	recorded bool
+	// hash is zero if the first response announced no usable digest.
+	hash     crypto.Hash
+	expected []byte
+}
+
+// digestAlgorithms maps the Repr-Digest algorithms that are verified to their hash, strongest first.
+var digestAlgorithms = []struct {
+	name string
+	hash crypto.Hash
+}{
+	{"sha-512", crypto.SHA512},
+	{"sha-256", crypto.SHA256},
+}
+
+// recordDigest records the digest announced by resp if it is the first response looked at.
+// It must be called with s.mu held.
+func (s *Seeker) recordDigest(resp *http.Response) {
+	if !s.cfg.verifyDigest || s.digest.recorded {
+		return
+	}
+	s.digest.recorded = true
+	if resp.Uncompressed || contentEncoding(resp.Header) != "" {
+		// The digest covers the encoded bytes, not those delivered.
+		return
+	}
+	if h, sum := parseReprDigest(resp.Header.Values("Repr-Digest")); sum != nil {
+		s.digest.hash, s.digest.expected = h, sum
+		return
+	}
+	// Content-MD5 covers the body of the response, which is the content only for a full response.
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	if sum, err := base64.StdEncoding.DecodeString(strings.TrimSpace(resp.Header.Get("Content-MD5"))); err == nil && len(sum) == crypto.MD5.Size() {
+		s.digest.hash, s.digest.expected = crypto.MD5, sum
+	}
+}
+
+// parseReprDigest returns the strongest digest of a Repr-Digest field as defined by RFC 9530,
+// a dictionary of algorithms to byte sequences such as sha-256=:base64:, or nil if it has none that is verified.
+func parseReprDigest(values []string) (crypto.Hash, []byte) {
+	digests := map[string][]byte{}
+	for _, value := range values {
+		for _, member := range strings.Split(value, ",") {
+			name, value, ok := strings.Cut(strings.TrimSpace(member), "=")
+			if !ok {
+				continue
+			}
+			value, _, _ = strings.Cut(value, ";")
+			value = strings.TrimSpace(value)
+			if len(value) < 2 || value[0] != ':' || value[len(value)-1] != ':' {
+				continue
+			}
+			sum, err := base64.StdEncoding.DecodeString(value[1 : len(value)-1])
+			if err != nil {
+				continue
+			}
+			digests[strings.ToLower(strings.TrimSpace(name))] = sum
+		}
+	}
+	for _, alg := range digestAlgorithms {
+		if sum, ok := digests[alg.name]; ok && len(sum) == alg.hash.Size() {
+			return alg.hash, sum
+		}
+	}
+	return 0, nil
+}
+
+// feedDigest hashes the bytes delivered at start that extend the bytes hashed from offset 0.
+func (s *Seeker) feedDigest(start int64, p []byte) {
+	if !s.cfg.verifyDigest {
+		return
+	}
+	if s.digestSum.h == nil {
+		s.mu.Lock()
+		h := s.digest.hash
+		s.mu.Unlock()
+		if h == 0 {
+			return
+		}
+		s.digestSum.h = h.New()
+	}
+	s.digestSum.write(start, p)
+}
+
+// checkDigestLocked is called once every byte of the content was delivered, before the transfer is complete.
+// It returns a *DigestError if every byte was hashed and the hash differs from the announced digest.
+// It must be called with s.mu held.
+func (s *Seeker) checkDigestLocked() error {
+	if s.digestSum.h == nil || s.digestSum.hashed != s.delivered {
+		return nil
+	}
+	if sum := s.digestSum.h.Sum(nil); !bytes.Equal(sum, s.digest.expected) {
+		return &DigestError{Hash: s.digest.hash, Expected: s.digest.expected, Actual: sum}
+	}
+	return nil
+}
+
+// verifyDigest is called when Read reaches the end of the content. It returns the *DigestError
+// found when the content was delivered, if any, and io.EOF otherwise.
+func (s *Seeker) verifyDigest() error {
+	if s.digestErr != nil {
+		return s.digestErr
+	}
+	return io.EOF
+}
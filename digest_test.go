@@ -0,0 +1,135 @@
+package httpseek
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestDigestVerification(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	sha := sha256.Sum256(content)
+	md := md5.Sum(content)
+	wrong := sha256.Sum256([]byte("Hello World?"))
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		seek    int64
+		wantErr error
+	}{
+		{
+			name:   "repr-digest",
+			header: http.Header{"Repr-Digest": {"sha-256=:" + base64.StdEncoding.EncodeToString(sha[:]) + ":, unknown=:AAAA:"}},
+		},
+		{
+			name:    "repr-digest mismatch",
+			header:  http.Header{"Repr-Digest": {"sha-256=:" + base64.StdEncoding.EncodeToString(wrong[:]) + ":"}},
+			wantErr: ErrDigestMismatch,
+		},
+		{
+			name:   "content-md5",
+			header: http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(md[:])}},
+		},
+		{
+			name:    "content-md5 mismatch",
+			header:  http.Header{"Content-Md5": {base64.StdEncoding.EncodeToString(wrong[:16])}},
+			wantErr: ErrDigestMismatch,
+		},
+		{
+			name:   "partial read",
+			header: http.Header{"Repr-Digest": {"sha-256=:" + base64.StdEncoding.EncodeToString(wrong[:]) + ":"}},
+			seek:   3,
+		},
+		{
+			name: "no digest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			flaky := testutil.ServeThenFail(5, errors.New("connection reset"))
+			flaky.Header = tt.header
+			transport := testutil.NewReplayTransport(content, flaky)
+			transport.ETag = `"v1"`
+			rsc := NewSeekerWithOptions(ctx, transport, req, WithRetry(0, time.Millisecond), WithDigestVerification())
+			defer rsc.Close()
+
+			want := content
+			if tt.seek > 0 {
+				if _, err := rsc.Seek(tt.seek, io.SeekStart); err != nil {
+					t.Fatal(err)
+				}
+				want = content[tt.seek:]
+			}
+			r := NewMustReader(rsc, func(retry int, err error) error {
+				if retry > 0 {
+					return err
+				}
+				return nil
+			})
+			data, err := io.ReadAll(r)
+			if string(data) != string(want) {
+				t.Fatalf("got %q, want %q", data, want)
+			}
+			if tt.wantErr == nil && err != nil || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			var digestErr *DigestError
+			if tt.wantErr != nil && !errors.As(err, &digestErr) {
+				t.Fatalf("got %T, want a *DigestError", err)
+			}
+		})
+	}
+}
+
+func TestDigestMismatchNotComplete(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	wrong := md5.Sum([]byte("Hello World?"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := testutil.NewReplayTransport(content, testutil.Respond(0, "Content-Md5", base64.StdEncoding.EncodeToString(wrong[:])))
+	var events, completed int
+	rsc := NewSeekerWithOptions(ctx, transport, req,
+		WithDigestVerification(),
+		WithEventListener(func(ev Event) {
+			if _, ok := ev.(TransferComplete); ok {
+				events++
+			}
+		}),
+		WithOnComplete(func(Summary) {
+			completed++
+		}),
+	)
+	defer rsc.Close()
+
+	// The last byte is delivered by the first Read, before the end of the content is reported.
+	if _, err := io.ReadFull(rsc, make([]byte, len(content))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Read(make([]byte, 1)); !errors.Is(err, ErrDigestMismatch) {
+		t.Fatalf("got error %v, want %v", err, ErrDigestMismatch)
+	}
+	if rsc.IsComplete() {
+		t.Fatal("transfer complete despite the digest mismatch")
+	}
+	if events != 0 || completed != 0 {
+		t.Fatalf("got %d TransferComplete events and %d completions, want none", events, completed)
+	}
+}
@@ -0,0 +1,213 @@
+package httpseek
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	diskChunkExt    = ".chunk"
+	diskChunkHeader = 12
+)
+
+var errChunkTooLarge = errors.New("chunk larger than the disk cache")
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// DiskCache is a Cache keeping each chunk in a file of a directory, so that chunks outlive the process
+// and are reused by later runs. Each file holds the length and a CRC-32C checksum of its chunk, and a file
+// that was corrupted or only partly written is removed when read instead of being served.
+// The files take at most maxBytes, the least recently used ones being removed first.
+// A DiskCache is safe for concurrent use, but a directory must not be used by two DiskCaches at once.
+type DiskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*diskEntry
+	used    int64
+}
+
+// diskEntry is a chunk file of a DiskCache.
+type diskEntry struct {
+	size     int64
+	lastUsed time.Time
+}
+
+// NewDiskCache returns a DiskCache keeping at most maxBytes of chunks in dir, which is created if needed.
+// Chunk files left in dir by an earlier DiskCache are reused, and removed if they exceed maxBytes.
+func NewDiskCache(dir string, maxBytes int64) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	c := &DiskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  map[string]*diskEntry{},
+	}
+	for _, file := range files {
+		if !file.Type().IsRegular() {
+			continue
+		}
+		if strings.HasSuffix(file.Name(), ".tmp") && strings.Contains(file.Name(), diskChunkExt+".") {
+			// A temporary file of a write that did not complete.
+			_ = os.Remove(filepath.Join(dir, file.Name()))
+			continue
+		}
+		if !strings.HasSuffix(file.Name(), diskChunkExt) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		c.entries[file.Name()] = &diskEntry{size: info.Size(), lastUsed: info.ModTime()}
+		c.used += info.Size()
+	}
+	c.mu.Lock()
+	c.evict("")
+	c.mu.Unlock()
+	return c, nil
+}
+
+// Used returns the number of bytes taken by the chunk files.
+func (c *DiskCache) Used() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.used
+}
+
+// chunkFileName returns the name of the file of the chunk at index of the content identified by key.
+func chunkFileName(key string, index int64) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16]) + "-" + strconv.FormatInt(index, 10) + diskChunkExt
+}
+
+// Get reads the chunk at index of the content identified by key, removing its file if it does not check out.
+func (c *DiskCache) Get(key string, index int64) ([]byte, bool) {
+	name := chunkFileName(key, index)
+	c.mu.Lock()
+	_, ok := c.entries[name]
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	path := filepath.Join(c.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		c.remove(name)
+		return nil, false
+	}
+	chunk, ok := decodeChunk(data)
+	if !ok {
+		c.remove(name)
+		return nil, false
+	}
+	now := time.Now()
+	c.mu.Lock()
+	if e, ok := c.entries[name]; ok {
+		e.lastUsed = now
+	}
+	c.mu.Unlock()
+	// The modification time keeps the order of use for later runs.
+	_ = os.Chtimes(path, now, now)
+	return chunk, true
+}
+
+// Put writes the chunk at index of the content identified by key to a temporary file renamed into place,
+// then removes the least recently used files if the cache is over its size.
+func (c *DiskCache) Put(key string, index int64, chunk []byte) error {
+	size := int64(diskChunkHeader + len(chunk))
+	if size > c.maxBytes {
+		return errChunkTooLarge
+	}
+	name := chunkFileName(key, index)
+	tmp, err := os.CreateTemp(c.dir, name+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(encodeChunk(chunk))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(c.dir, name))
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[name]; ok {
+		c.used -= e.size
+	}
+	c.entries[name] = &diskEntry{size: size, lastUsed: time.Now()}
+	c.used += size
+	c.evict(name)
+	return nil
+}
+
+// evict removes the least recently used files other than keep until the cache is within its size.
+// It must be called with c.mu held.
+func (c *DiskCache) evict(keep string) {
+	for c.used > c.maxBytes {
+		var oldest string
+		for name, e := range c.entries {
+			if name != keep && (oldest == "" || e.lastUsed.Before(c.entries[oldest].lastUsed)) {
+				oldest = name
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		c.used -= c.entries[oldest].size
+		delete(c.entries, oldest)
+		_ = os.Remove(filepath.Join(c.dir, oldest))
+	}
+}
+
+// remove removes the file of a chunk that could not be read.
+func (c *DiskCache) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[name]; ok {
+		c.used -= e.size
+		delete(c.entries, name)
+	}
+	_ = os.Remove(filepath.Join(c.dir, name))
+}
+
+// encodeChunk returns the content of the file of chunk: its length and checksum, then its bytes.
+func encodeChunk(chunk []byte) []byte {
+	data := make([]byte, diskChunkHeader, diskChunkHeader+len(chunk))
+	binary.BigEndian.PutUint64(data, uint64(len(chunk)))
+	binary.BigEndian.PutUint32(data[8:], crc32.Checksum(chunk, crc32c))
+	return append(data, chunk...)
+}
+
+// decodeChunk returns the chunk of a file, or false if its length or checksum does not match.
+func decodeChunk(data []byte) ([]byte, bool) {
+	if len(data) < diskChunkHeader {
+		return nil, false
+	}
+	chunk := data[diskChunkHeader:]
+	if binary.BigEndian.Uint64(data) != uint64(len(chunk)) || binary.BigEndian.Uint32(data[8:]) != crc32.Checksum(chunk, crc32c) {
+		return nil, false
+	}
+	return chunk, true
+}
@@ -0,0 +1,131 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	dir := t.TempDir()
+
+	// run reads the content with a new DiskCache on dir, as a new process would, and returns the requests.
+	run := func() []testutil.Request {
+		t.Helper()
+		cache, err := NewDiskCache(dir, 1<<20)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transport := testutil.NewReplayTransport(content)
+		transport.ETag = `"v1"`
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, transport, req, WithChunkCache(cache, 100), WithPreflight())
+		defer rsc.Close()
+		got, err := io.ReadAll(rsc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Fatalf("got %q, want %q", got, content)
+		}
+		return transport.Requests()
+	}
+
+	if requests := run(); len(requests) != 2 {
+		t.Fatalf("got %d requests, want the HEAD and the GET", len(requests))
+	}
+	// The second run only learns the ETag; every chunk is read from disk.
+	if requests := run(); len(requests) != 1 || requests[0].Method != http.MethodHead {
+		t.Fatalf("got requests %+v, want only the HEAD", requests)
+	}
+
+	// A truncated chunk file is not served, and the content is requested from there.
+	key := `http://example.com/file "v1" 1000 100`
+	path := filepath.Join(dir, chunkFileName(key, 5))
+	if err := os.Truncate(path, 50); err != nil {
+		t.Fatal(err)
+	}
+	if requests := run(); len(requests) != 2 || requests[1].Range != "bytes=500-" {
+		t.Fatalf("got requests %+v, want the HEAD and a GET from 500", requests)
+	}
+	if data, err := os.ReadFile(path); err != nil || !bytes.Equal(data, encodeChunk(content[500:600])) {
+		t.Fatalf("got chunk file %q, %v, want it rewritten", data, err)
+	}
+}
+
+func TestDiskCacheEviction(t *testing.T) {
+	chunk := []byte("0123456789")
+	size := int64(diskChunkHeader + len(chunk))
+	cache, err := NewDiskCache(t.TempDir(), 2*size)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := int64(0); i < 2; i++ {
+		if err := cache.Put("key", i, chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Reading chunk 0 makes chunk 1 the least recently used.
+	if _, ok := cache.Get("key", 0); !ok {
+		t.Fatal("chunk 0 not found")
+	}
+	if err := cache.Put("key", 2, chunk); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Get("key", 1); ok {
+		t.Fatal("chunk 1 not evicted")
+	}
+	for _, i := range []int64{0, 2} {
+		if got, ok := cache.Get("key", i); !ok || !bytes.Equal(got, chunk) {
+			t.Fatalf("got chunk %d %q, %v, want %q", i, got, ok, chunk)
+		}
+	}
+	if used := cache.Used(); used != 2*size {
+		t.Fatalf("got %d bytes used, want %d", used, 2*size)
+	}
+	if err := cache.Put("key", 3, make([]byte, 2*size)); err == nil {
+		t.Fatal("got no error for a chunk larger than the cache")
+	}
+}
+
+func TestDiskCacheChunkSize(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	dir := t.TempDir()
+	cache, err := NewDiskCache(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := testutil.NewReplayTransport(content)
+	transport.ETag = `"v1"`
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithChunkCache(cache, 0))
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	// The content fits in a single chunk of the default size.
+	key := `http://example.com/file "v1" 1000 4194304`
+	if data, err := os.ReadFile(filepath.Join(dir, chunkFileName(key, 0))); err != nil || !bytes.Equal(data, encodeChunk(content)) {
+		t.Fatalf("got chunk file %q, %v, want the whole content", data, err)
+	}
+}
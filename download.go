@@ -0,0 +1,73 @@
+package httpseek
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+const defaultDownloadConcurrency = 4
+
+// DownloadOptions configures Download.
+type DownloadOptions struct {
+	// SegmentSize is the size of the segments fetched with their own bounded range requests.
+	// The default is 4 MiB.
+	SegmentSize int64
+	// Concurrency is the number of segments fetched at once. The default is 4.
+	Concurrency int
+	// Checkpointer, if not nil, records the segments written, and the segments it reports as completed
+	// are not fetched, so that a Download that failed is resumed by running it again. See WithCheckpointer.
+	Checkpointer Checkpointer
+}
+
+// Download fetches the whole content of s into dst in segments, Concurrency of them at once, each with its own
+// bounded range request, as RestoreTo does, leaving the open stream of s untouched. A segment that fails is
+// fetched again on its own, up to the retry count of s. The first segment that cannot be fetched, or the
+// cancellation of ctx, stops the segments in flight, and Download returns that first error.
+// Once every segment is written, Download checks that the bytes written add up to Size,
+// or with a Checkpointer that the completed ranges cover the content.
+func Download(ctx context.Context, s *Seeker, dst io.WriterAt, opts DownloadOptions) error {
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = defaultChunkSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultDownloadConcurrency
+	}
+	w := &countingWriterAt{w: dst}
+	restoreOpts := []RestoreOption{WithChunkSize(opts.SegmentSize), WithParallel(opts.Concurrency)}
+	if opts.Checkpointer != nil {
+		restoreOpts = append(restoreOpts, WithCheckpointer(opts.Checkpointer))
+	}
+	err := s.RestoreTo(ctx, w, restoreOpts...)
+	if err != nil {
+		return err
+	}
+	if opts.Checkpointer != nil {
+		// Segments written by an earlier run are not written again.
+		done := NewExtentSet()
+		for _, r := range opts.Checkpointer.Completed() {
+			done.Add(r.Start, r.End)
+		}
+		if size := s.Size(); !done.Contains(0, size) {
+			return fmt.Errorf("%w: the completed ranges do not cover the %d bytes", io.ErrShortWrite, size)
+		}
+		return nil
+	}
+	if written, size := w.n.Load(), s.Size(); written != size {
+		return fmt.Errorf("%w: wrote %d of %d bytes", io.ErrShortWrite, written, size)
+	}
+	return nil
+}
+
+// countingWriterAt counts the bytes written through it.
+type countingWriterAt struct {
+	w io.WriterAt
+	n atomic.Int64
+}
+
+func (c *countingWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	n, err := c.w.WriteAt(p, off)
+	c.n.Add(int64(n))
+	return n, err
+}
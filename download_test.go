@@ -0,0 +1,186 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// abortingWriter aborts the response after n bytes of body.
+type abortingWriter struct {
+	http.ResponseWriter
+	n int
+}
+
+func (w *abortingWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		w.ResponseWriter.Write(p[:w.n])
+		panic(http.ErrAbortHandler)
+	}
+	w.n -= len(p)
+	return w.ResponseWriter.Write(p)
+}
+
+func TestDownload(t *testing.T) {
+	ctx := context.Background()
+	content := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	var requests, broken atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Range") == "bytes=131072-196607" && broken.Add(1) == 1 {
+			w = &abortingWriter{ResponseWriter: w, n: 100}
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithExpectedSize(int64(len(content))), WithRetry(2, time.Millisecond))
+	defer rsc.Close()
+
+	dst := &memWriterAt{buf: make([]byte, len(content))}
+	if err := Download(ctx, rsc, dst, DownloadOptions{SegmentSize: 64 << 10, Concurrency: 8}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dst.buf, content) {
+		t.Fatal("downloaded content differs")
+	}
+	// 16 segments, one of them fetched twice.
+	if got := requests.Load(); got != 17 {
+		t.Fatalf("got %d requests, want 17", got)
+	}
+}
+
+func TestDownloadCheckpointer(t *testing.T) {
+	ctx := context.Background()
+	content := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(content)
+
+	var requests atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("Range") == "bytes=131072-196607" && failing.Load() {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	path := CheckpointPath(filepath.Join(t.TempDir(), "out"))
+	dst := &memWriterAt{buf: make([]byte, len(content))}
+	download := func() error {
+		t.Helper()
+		checkpointer, err := NewFileCheckpointer(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithExpectedSize(int64(len(content))))
+		defer rsc.Close()
+		return Download(ctx, rsc, dst, DownloadOptions{SegmentSize: 64 << 10, Concurrency: 1, Checkpointer: checkpointer})
+	}
+
+	// The first run writes two segments and fails on the third.
+	if err := download(); err == nil {
+		t.Fatal("got no error from the failing segment")
+	}
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("got %d requests, want 3", got)
+	}
+
+	// The second run fetches only the 14 segments left.
+	failing.Store(false)
+	requests.Store(0)
+	if err := download(); err != nil {
+		t.Fatal(err)
+	}
+	if got := requests.Load(); got != 14 {
+		t.Fatalf("got %d requests, want 14", got)
+	}
+	if !bytes.Equal(dst.buf, content) {
+		t.Fatal("downloaded content differs")
+	}
+}
+
+// shortWriterAt drops the last byte of every write without reporting it.
+type shortWriterAt struct{}
+
+func (shortWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	return len(p) - 1, nil
+}
+
+func TestDownloadShortWrite(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if err := Download(ctx, rsc, shortWriterAt{}, DownloadOptions{SegmentSize: 4}); !errors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("got %v, want %v", err, io.ErrShortWrite)
+	}
+}
+
+func TestDownloadCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	content := make([]byte, 1<<20)
+
+	started := make(chan struct{}, 16)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithExpectedSize(int64(len(content))))
+	defer rsc.Close()
+
+	go func() {
+		<-started
+		cancel()
+	}()
+	done := make(chan error, 1)
+	go func() {
+		done <- Download(ctx, rsc, &memWriterAt{buf: make([]byte, len(content))}, DownloadOptions{SegmentSize: 64 << 10, Concurrency: 4})
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Download did not return after the cancellation")
+	}
+}
@@ -0,0 +1,180 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newEdgeServer serves content; without HEAD support the size must be learned from a range probe.
+func newEdgeServer(content []byte, head bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead && !head {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+}
+
+func TestEdgeCases(t *testing.T) {
+	ctx := context.Background()
+
+	for _, content := range [][]byte{{}, []byte("x")} {
+		for _, head := range []bool{true, false} {
+			name := strconv.Itoa(len(content)) + " bytes"
+			if !head {
+				name += " without HEAD"
+			}
+			t.Run(name, func(t *testing.T) {
+				s := newEdgeServer(content, head)
+				defer s.Close()
+				size := int64(len(content))
+
+				newSeeker := func(opts ...Option) *Seeker {
+					req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+					if err != nil {
+						t.Fatal(err)
+					}
+					return NewSeekerWithOptions(ctx, s.Client().Transport, req, opts...)
+				}
+
+				t.Run("read all", func(t *testing.T) {
+					rsc := newSeeker()
+					defer rsc.Close()
+					got, err := io.ReadAll(rsc)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(got, content) || rsc.Size() != size {
+						t.Fatalf("got %q with size %d, want %q with size %d", got, rsc.Size(), content, size)
+					}
+					if !rsc.IsComplete() {
+						t.Fatal("got incomplete, want complete")
+					}
+				})
+
+				t.Run("seek end", func(t *testing.T) {
+					rsc := newSeeker()
+					defer rsc.Close()
+					off, err := rsc.Seek(0, io.SeekEnd)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if off != size || rsc.Size() != size {
+						t.Fatalf("got offset %d and size %d, want %d", off, rsc.Size(), size)
+					}
+					requests := rsc.Stats().Requests
+					if n, err := rsc.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+						t.Fatalf("got %d, %v, want 0, EOF", n, err)
+					}
+					if got := rsc.Stats().Requests; got != requests {
+						t.Fatalf("got %d requests for reading at the end, want none", got-requests)
+					}
+				})
+
+				t.Run("seek last byte", func(t *testing.T) {
+					if size == 0 {
+						t.Skip()
+					}
+					rsc := newSeeker()
+					defer rsc.Close()
+					if _, err := rsc.Seek(-1, io.SeekEnd); err != nil {
+						t.Fatal(err)
+					}
+					got, err := io.ReadAll(rsc)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(got, content) {
+						t.Fatalf("got %q, want %q", got, content)
+					}
+				})
+
+				t.Run("read at", func(t *testing.T) {
+					rsc := newSeeker()
+					defer rsc.Close()
+					buf := make([]byte, 4)
+					n, err := rsc.ReadAt(buf, 0)
+					if err != io.EOF || !bytes.Equal(buf[:n], content) {
+						t.Fatalf("got %q, %v, want %q, EOF", buf[:n], err, content)
+					}
+					if rsc.Size() != size {
+						t.Fatalf("got size %d, want %d", rsc.Size(), size)
+					}
+					requests := rsc.Stats().Requests
+					if n, err := rsc.ReadAt(buf, size); n != 0 || err != io.EOF {
+						t.Fatalf("got %d, %v at the end, want 0, EOF", n, err)
+					}
+					if got := rsc.Stats().Requests; got != requests {
+						t.Fatalf("got %d requests for reading at the end, want none", got-requests)
+					}
+				})
+
+				t.Run("read at end", func(t *testing.T) {
+					rsc := newSeeker()
+					defer rsc.Close()
+					if n, err := rsc.ReadAt(make([]byte, 4), size); n != 0 || err != io.EOF {
+						t.Fatalf("got %d, %v, want 0, EOF", n, err)
+					}
+					if rsc.Size() != size {
+						t.Fatalf("got size %d, want %d", rsc.Size(), size)
+					}
+				})
+
+				t.Run("tail", func(t *testing.T) {
+					rsc := newSeeker()
+					defer rsc.Close()
+					r, err := rsc.Tail(4)
+					if err != nil {
+						t.Fatal(err)
+					}
+					got, err := io.ReadAll(r)
+					r.Close()
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(got, content) || rsc.Size() != size {
+						t.Fatalf("got %q with size %d, want %q with size %d", got, rsc.Size(), content, size)
+					}
+				})
+
+				t.Run("tail prefetch", func(t *testing.T) {
+					rsc := newSeeker(WithTailPrefetch(4))
+					defer rsc.Close()
+					if _, err := rsc.Seek(0, io.SeekEnd); err != nil {
+						t.Fatal(err)
+					}
+					if rsc.Size() != size {
+						t.Fatalf("got size %d, want %d", rsc.Size(), size)
+					}
+					if n, err := rsc.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+						t.Fatalf("got %d, %v, want 0, EOF", n, err)
+					}
+				})
+
+				t.Run("restore", func(t *testing.T) {
+					rsc := newSeeker()
+					defer rsc.Close()
+					dst := &memWriterAt{buf: make([]byte, size)}
+					checkpointer, err := NewFileCheckpointer(filepath.Join(t.TempDir(), "checkpoint.json"))
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := rsc.RestoreTo(ctx, dst, WithCheckpointer(checkpointer)); err != nil {
+						t.Fatal(err)
+					}
+					if !bytes.Equal(dst.buf, content) {
+						t.Fatalf("got %q, want %q", dst.buf, content)
+					}
+				})
+			})
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package httpseek
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ErrEncodedRange is matched by errors.Is when a response to a range request carries a Content-Encoding,
+// so its bytes do not line up with the offsets of the content.
+var ErrEncodedRange = errors.New("range response is content-encoded")
+
+// EncodedRangeError is returned when a response to a range request carries a Content-Encoding other than identity.
+type EncodedRangeError struct {
+	// Offset is the offset of the request.
+	Offset int64
+	// Encoding is the Content-Encoding of the response.
+	Encoding string
+}
+
+func (e *EncodedRangeError) Error() string {
+	return fmt.Sprintf("range response at offset %d has Content-Encoding %q", e.Offset, e.Encoding)
+}
+
+func (e *EncodedRangeError) Is(target error) bool {
+	return target == ErrEncodedRange
+}
+
+// contentEncoding returns the lowercased Content-Encoding of a response, or "" for none or identity.
+func contentEncoding(header http.Header) string {
+	encoding := strings.ToLower(strings.TrimSpace(header.Get("Content-Encoding")))
+	if encoding == "identity" {
+		return ""
+	}
+	return encoding
+}
+
+// decodeFromStart serves offset from a request for the whole content, decoding its Content-Encoding
+// and discarding the bytes before offset, as enabled by WithEncodedRangeFallback.
+func (s *Seeker) decodeFromStart(ctx context.Context, offset int64) (io.ReadCloser, int64, *http.Response, error) {
+	r, _, resp, err := s.open(ctx, 0, -1)
+	if err != nil {
+		return nil, -1, nil, err
+	}
+	if resp != nil && resp.StatusCode != http.StatusOK {
+		_ = r.Close()
+		return nil, -1, nil, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+
+	body := r
+	if resp != nil {
+		var decoded io.ReadCloser
+		switch encoding := contentEncoding(resp.Header); encoding {
+		case "":
+		case "gzip", "x-gzip":
+			decoded, err = gzip.NewReader(r)
+		case "deflate":
+			decoded, err = zlib.NewReader(r)
+		default:
+			err = &EncodedRangeError{Offset: offset, Encoding: encoding}
+		}
+		if err != nil {
+			_ = r.Close()
+			return nil, -1, nil, err
+		}
+		if decoded != nil {
+			body = struct {
+				io.Reader
+				io.Closer
+			}{
+				Reader: decoded,
+				Closer: r,
+			}
+		}
+	}
+
+	s.mu.Lock()
+	s.stats.WastedBytes += offset
+	size := s.size
+	s.mu.Unlock()
+	err = discard(body, offset)
+	if err != nil {
+		_ = body.Close()
+		return nil, -1, nil, err
+	}
+	return body, size, resp, nil
+}
@@ -0,0 +1,105 @@
+package httpseek
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newGzipRangeServer returns a server that gzip-encodes its range responses, and its full responses if gzipAll is set.
+func newGzipRangeServer(content []byte, gzipAll bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start int64
+		ranged := r.Header.Get("Range") != ""
+		if ranged {
+			if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		end := int64(len(content))
+		body := content[start:end]
+		if ranged || gzipAll {
+			var buf bytes.Buffer
+			zw := gzip.NewWriter(&buf)
+			zw.Write(body)
+			zw.Close()
+			body = buf.Bytes()
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		if ranged {
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		w.Write(body)
+	}))
+}
+
+func TestEncodedRange(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+
+	s := newGzipRangeServer(content, false)
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	_, err = rsc.Read(make([]byte, 6))
+	var encodedErr *EncodedRangeError
+	if !errors.As(err, &encodedErr) || encodedErr.Encoding != "gzip" || encodedErr.Offset != 6 {
+		t.Fatalf("got %v, want an EncodedRangeError for gzip at 6", err)
+	}
+	if !errors.Is(err, ErrEncodedRange) || !errors.Is(err, ErrNotResumable) {
+		t.Fatalf("got %v, want ErrEncodedRange and ErrNotResumable", err)
+	}
+
+	if _, err := rsc.ReadAt(make([]byte, 4), 2); !errors.Is(err, ErrEncodedRange) {
+		t.Fatalf("got %v from ReadAt, want %v", err, ErrEncodedRange)
+	}
+}
+
+func TestEncodedRangeFallback(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+
+	for _, gzipAll := range []bool{false, true} {
+		s := newGzipRangeServer(content, gzipAll)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithEncodedRangeFallback())
+
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(rsc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "World!" {
+			t.Fatalf("gzipAll=%t: got %q, want %q", gzipAll, got, "World!")
+		}
+		if wasted := rsc.Stats().WastedBytes; wasted != 6 {
+			t.Fatalf("gzipAll=%t: got %d wasted bytes, want 6", gzipAll, wasted)
+		}
+		rsc.Close()
+		s.Close()
+	}
+}
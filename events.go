@@ -0,0 +1,111 @@
+package httpseek
+
+import (
+	"errors"
+)
+
+// Event is something the Seeker learned or did, delivered to the listeners set by WithEventListener.
+// It is one of SizeDiscovered, ValidatorsDiscovered, ResumeStarted, ResumeFinished,
+// ContentChangeSuspected and TransferComplete.
+type Event interface {
+	isEvent()
+}
+
+// SizeDiscovered is delivered when the total size is learned or an accepted size change updates it.
+type SizeDiscovered struct {
+	// Size is the new size.
+	Size int64
+	// Old is the size known before, or -1 if it was unknown.
+	Old int64
+}
+
+// ValidatorsDiscovered is delivered when the first response carrying an ETag or Last-Modified is received.
+type ValidatorsDiscovered struct {
+	ETag         string
+	LastModified string
+}
+
+// ResumeStarted is delivered before the Seeker requests the content again at Offset after the stream broke.
+type ResumeStarted struct {
+	// Offset is where the content is requested again.
+	Offset int64
+	// Cause is the failure that broke the stream.
+	Cause error
+}
+
+// ResumeFinished is delivered when the request started by ResumeStarted succeeded or failed.
+type ResumeFinished struct {
+	// Offset is where the content was requested again.
+	Offset int64
+	// Err is nil if the stream was resumed, or the failure otherwise.
+	Err error
+}
+
+// ContentChangeSuspected is delivered when a response suggests that the content changed
+// since the first response, such as a changed ETag, size or Content-Type.
+type ContentChangeSuspected struct {
+	// Offset is the offset of the request that revealed the change.
+	Offset int64
+	// Err is the error reporting the change.
+	Err error
+}
+
+// TransferComplete is delivered once, when the last byte of the content has been delivered.
+// See IsComplete for when a transfer counts as complete.
+type TransferComplete struct {
+	Summary Summary
+}
+
+func (SizeDiscovered) isEvent()         {}
+func (ValidatorsDiscovered) isEvent()   {}
+func (ResumeStarted) isEvent()          {}
+func (ResumeFinished) isEvent()         {}
+func (ContentChangeSuspected) isEvent() {}
+func (TransferComplete) isEvent()       {}
+
+// emitLocked queues ev for the listeners. It must be called with s.mu held.
+func (s *Seeker) emitLocked(ev Event) {
+	if len(s.cfg.listeners) == 0 {
+		return
+	}
+	s.events = append(s.events, ev)
+}
+
+// emit queues ev for the listeners.
+func (s *Seeker) emit(ev Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emitLocked(ev)
+}
+
+// flushEvents delivers the queued events to the listeners, in the order they happened.
+// Deliveries never overlap, so listeners see a single sequence.
+func (s *Seeker) flushEvents() {
+	if len(s.cfg.listeners) == 0 {
+		return
+	}
+	s.deliverMu.Lock()
+	defer s.deliverMu.Unlock()
+	for {
+		s.mu.Lock()
+		events := s.events
+		s.events = nil
+		s.mu.Unlock()
+		if len(events) == 0 {
+			return
+		}
+		for _, ev := range events {
+			for _, listener := range s.cfg.listeners {
+				listener(ev)
+			}
+		}
+	}
+}
+
+// suspectChange reports err as a ContentChangeSuspected if it tells that the content changed.
+func (s *Seeker) suspectChange(offset int64, err error) {
+	var contentTypeErr *ContentTypeChangedError
+	if errors.Is(err, ErrContentChanged) || errors.Is(err, ErrSizeChanged) || errors.As(err, &contentTypeErr) {
+		s.emit(ContentChangeSuspected{Offset: offset, Err: err})
+	}
+}
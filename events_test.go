@@ -0,0 +1,125 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+// describeEvent returns a comparable description of ev, with errors reduced to whether they are set.
+func describeEvent(ev Event) string {
+	switch ev := ev.(type) {
+	case SizeDiscovered:
+		return fmt.Sprintf("size %d from %d", ev.Size, ev.Old)
+	case ValidatorsDiscovered:
+		return fmt.Sprintf("validators %s %q", ev.ETag, ev.LastModified)
+	case ResumeStarted:
+		return fmt.Sprintf("resume at %d after %v", ev.Offset, ev.Cause)
+	case ResumeFinished:
+		return fmt.Sprintf("resumed at %d failed=%t", ev.Offset, ev.Err != nil)
+	case ContentChangeSuspected:
+		return fmt.Sprintf("change at %d changed=%t", ev.Offset, errors.Is(ev.Err, ErrContentChanged))
+	case TransferComplete:
+		return fmt.Sprintf("complete %d", ev.Summary.Size)
+	}
+	return fmt.Sprintf("unknown %T", ev)
+}
+
+func TestEventListener(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	cause := errors.New("connection reset")
+
+	flaky := testutil.ServeThenFail(6, cause)
+	changed := testutil.Respond(http.StatusPartialContent, "Content-Range", "bytes 6-12/13")
+	changed.Body = []byte("World!!")
+
+	tests := []struct {
+		name    string
+		steps   []testutil.Step
+		wantErr error
+		want    []string
+	}{
+		{
+			name:  "resumed",
+			steps: []testutil.Step{flaky},
+			want: []string{
+				`validators "v1" ""`,
+				"size 12 from -1",
+				"resume at 6 after connection reset",
+				"resumed at 6 failed=false",
+				"complete 12",
+			},
+		},
+		{
+			name:    "changed",
+			steps:   []testutil.Step{flaky, changed},
+			wantErr: ErrContentChanged,
+			want: []string{
+				`validators "v1" ""`,
+				"size 12 from -1",
+				"resume at 6 after connection reset",
+				"change at 6 changed=true",
+				"resumed at 6 failed=true",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			var sizes []int64
+			var completed int
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			transport := testutil.NewReplayTransport(content, tt.steps...)
+			transport.ETag = `"v1"`
+			rsc := NewSeekerWithOptions(ctx, transport, req,
+				WithRetry(0, time.Millisecond),
+				WithEventListener(func(ev Event) {
+					got = append(got, describeEvent(ev))
+				}),
+				WithOnSizeKnown(func(size int64) {
+					sizes = append(sizes, size)
+				}),
+				WithOnComplete(func(Summary) {
+					completed++
+				}),
+			)
+			defer rsc.Close()
+
+			r := NewMustReader(rsc, func(retry int, err error) error {
+				if retry > 0 {
+					return err
+				}
+				return nil
+			})
+			data, err := io.ReadAll(r)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil || string(data) != string(content) {
+				t.Fatalf("got %q, %v, want %q", data, err, content)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got events\n%q\nwant\n%q", got, tt.want)
+			}
+			if !reflect.DeepEqual(sizes, []int64{12}) {
+				t.Errorf("got sizes %v from WithOnSizeKnown, want [12]", sizes)
+			}
+			if wantCompleted := map[bool]int{true: 0, false: 1}[tt.wantErr != nil]; completed != wantCompleted {
+				t.Errorf("got %d completions from WithOnComplete, want %d", completed, wantCompleted)
+			}
+		})
+	}
+}
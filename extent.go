@@ -0,0 +1,227 @@
+package httpseek
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Validator identifies the version of a content: the ETag and Last-Modified headers
+// and the total size that the server reported for it. Empty fields and a negative size are unknown.
+type Validator struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Size         int64  `json:"size"`
+}
+
+// IsZero reports whether nothing is known about the version.
+func (v Validator) IsZero() bool {
+	return v.ETag == "" && v.LastModified == "" && v.Size < 0
+}
+
+// mismatch returns the first field in which v and other disagree, comparing only the fields both know,
+// with the ETag taking precedence over Last-Modified.
+func (v Validator) mismatch(other Validator) (field, oldValue, newValue string, ok bool) {
+	switch {
+	case v.ETag != "" && other.ETag != "":
+		if v.ETag != other.ETag {
+			return "ETag", v.ETag, other.ETag, true
+		}
+	case v.LastModified != "" && other.LastModified != "":
+		if v.LastModified != other.LastModified {
+			return "Last-Modified", v.LastModified, other.LastModified, true
+		}
+	}
+	if v.Size >= 0 && other.Size >= 0 && v.Size != other.Size {
+		return "Size", strconv.FormatInt(v.Size, 10), strconv.FormatInt(other.Size, 10), true
+	}
+	return "", "", "", false
+}
+
+// ExtentSet records which byte ranges of a content are present,
+// together with the Validator of the version they were taken from.
+// It serializes to JSON for persistence and is safe for concurrent use.
+type ExtentSet struct {
+	mut       sync.Mutex
+	validator Validator
+	ranges    []Range
+}
+
+// NewExtentSet returns an empty ExtentSet whose version is not known yet.
+func NewExtentSet() *ExtentSet {
+	return &ExtentSet{validator: Validator{Size: -1}}
+}
+
+// Validator returns the version of the content the extents were taken from.
+func (e *ExtentSet) Validator() Validator {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	return e.validator
+}
+
+// SetValidator sets the version of the content the extents were taken from.
+func (e *ExtentSet) SetValidator(validator Validator) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	e.validator = validator
+}
+
+// Add records [start, end) as present.
+func (e *ExtentSet) Add(start, end int64) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	e.ranges = addRange(e.ranges, Range{Start: start, End: end})
+}
+
+// Remove records [start, end) as absent.
+func (e *ExtentSet) Remove(start, end int64) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	e.ranges = removeRange(e.ranges, Range{Start: start, End: end})
+}
+
+// Merge adds the ranges present in other.
+func (e *ExtentSet) Merge(other *ExtentSet) {
+	ranges := other.Ranges()
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	for _, r := range ranges {
+		e.ranges = addRange(e.ranges, r)
+	}
+}
+
+// Ranges returns the present ranges, sorted and merged.
+func (e *ExtentSet) Ranges() []Range {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	return append([]Range(nil), e.ranges...)
+}
+
+// Contains reports whether all of [start, end) is present.
+func (e *ExtentSet) Contains(start, end int64) bool {
+	if start >= end {
+		return true
+	}
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	i := searchRanges(e.ranges, start)
+	return i < len(e.ranges) && e.ranges[i].Start <= start && e.ranges[i].End >= end
+}
+
+// Covered returns how many bytes of [start, end) are present.
+func (e *ExtentSet) Covered(start, end int64) int64 {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	var n int64
+	for i := searchRanges(e.ranges, start); i < len(e.ranges) && e.ranges[i].Start < end; i++ {
+		n += min(e.ranges[i].End, end) - max(e.ranges[i].Start, start)
+	}
+	return max(n, 0)
+}
+
+// Missing returns the parts of [start, end) that are not present, in order.
+func (e *ExtentSet) Missing(start, end int64) []Range {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	var missing []Range
+	for i := searchRanges(e.ranges, start); i < len(e.ranges); i++ {
+		r := e.ranges[i]
+		if r.Start >= end {
+			break
+		}
+		if r.Start > start {
+			missing = append(missing, Range{Start: start, End: r.Start})
+		}
+		start = r.End
+	}
+	if start < end {
+		missing = append(missing, Range{Start: start, End: end})
+	}
+	return missing
+}
+
+// searchRanges returns the index of the first of the sorted, merged ranges that ends after offset.
+func searchRanges(ranges []Range, offset int64) int {
+	return sort.Search(len(ranges), func(i int) bool {
+		return ranges[i].End > offset
+	})
+}
+
+// addRange adds r to the sorted, merged ranges, merging it with the ones it overlaps or touches.
+// Finding its place takes O(log n).
+func addRange(ranges []Range, r Range) []Range {
+	if r.End <= r.Start {
+		return ranges
+	}
+	// The first range that ends at or after r.Start and the first that starts after r.End.
+	i := sort.Search(len(ranges), func(i int) bool {
+		return ranges[i].End >= r.Start
+	})
+	j := i + sort.Search(len(ranges)-i, func(k int) bool {
+		return ranges[i+k].Start > r.End
+	})
+	if i < j {
+		r.Start = min(r.Start, ranges[i].Start)
+		r.End = max(r.End, ranges[j-1].End)
+	}
+	return slices.Replace(ranges, i, j, r)
+}
+
+// removeRange removes r from the sorted, merged ranges, splitting the range that contains it.
+func removeRange(ranges []Range, r Range) []Range {
+	if r.End <= r.Start {
+		return ranges
+	}
+	i := searchRanges(ranges, r.Start)
+	j := i + sort.Search(len(ranges)-i, func(k int) bool {
+		return ranges[i+k].Start >= r.End
+	})
+	var kept []Range
+	if i < j && ranges[i].Start < r.Start {
+		kept = append(kept, Range{Start: ranges[i].Start, End: r.Start})
+	}
+	if i < j && ranges[j-1].End > r.End {
+		kept = append(kept, Range{Start: r.End, End: ranges[j-1].End})
+	}
+	return slices.Replace(ranges, i, j, kept...)
+}
+
+type extentSetJSON struct {
+	Validator Validator `json:"validator"`
+	Ranges    []Range   `json:"ranges"`
+}
+
+func (e *ExtentSet) MarshalJSON() ([]byte, error) {
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	return json.Marshal(extentSetJSON{
+		Validator: e.validator,
+		Ranges:    e.ranges,
+	})
+}
+
+func (e *ExtentSet) UnmarshalJSON(data []byte) error {
+	var v extentSetJSON
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	e.mut.Lock()
+	defer e.mut.Unlock()
+	e.validator = v.Validator
+	e.ranges = mergeRanges(v.Ranges)
+	return nil
+}
+
+// Validator returns what the Seeker has learned about the version of the content.
+func (s *Seeker) Validator() Validator {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Validator{
+		ETag:         s.etag,
+		LastModified: s.lastModified,
+		Size:         s.size,
+	}
+}
@@ -0,0 +1,137 @@
+package httpseek
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestExtentSet(t *testing.T) {
+	e := NewExtentSet()
+	e.Add(10, 20)
+	e.Add(30, 40)
+	e.Add(18, 25)
+
+	if want := []Range{{Start: 10, End: 25}, {Start: 30, End: 40}}; !reflect.DeepEqual(e.Ranges(), want) {
+		t.Fatalf("got %v, want %v", e.Ranges(), want)
+	}
+
+	tests := []struct {
+		start, end int64
+		want       []Range
+	}{
+		{start: 0, end: 50, want: []Range{{Start: 0, End: 10}, {Start: 25, End: 30}, {Start: 40, End: 50}}},
+		{start: 12, end: 22},
+		{start: 20, end: 35, want: []Range{{Start: 25, End: 30}}},
+		{start: 40, end: 45, want: []Range{{Start: 40, End: 45}}},
+	}
+	for _, tt := range tests {
+		if got := e.Missing(tt.start, tt.end); !reflect.DeepEqual(got, tt.want) {
+			t.Fatalf("Missing(%d, %d): got %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+
+	e.SetValidator(Validator{ETag: `"x"`, Size: 50})
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ExtentSet
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded.Ranges(), e.Ranges()) || decoded.Validator() != e.Validator() {
+		t.Fatalf("got %s after a round trip", data)
+	}
+}
+
+// TestExtentSetModel checks random sequences of Add, Remove and Merge against a bitmap.
+func TestExtentSetModel(t *testing.T) {
+	const size = 64
+	rng := rand.New(rand.NewSource(1))
+	randomRange := func() (int64, int64) {
+		start := rng.Int63n(size)
+		return start, start + rng.Int63n(size-start+1)
+	}
+
+	for round := 0; round < 200; round++ {
+		e := NewExtentSet()
+		var model [size]bool
+		for op := 0; op < 20; op++ {
+			start, end := randomRange()
+			switch rng.Intn(3) {
+			case 0:
+				e.Add(start, end)
+				for i := start; i < end; i++ {
+					model[i] = true
+				}
+			case 1:
+				e.Remove(start, end)
+				for i := start; i < end; i++ {
+					model[i] = false
+				}
+			case 2:
+				other := NewExtentSet()
+				other.Add(start, end)
+				other.Add(end+2, end+4)
+				e.Merge(other)
+				for i := start; i < end; i++ {
+					model[i] = true
+				}
+				for i := end + 2; i < min(end+4, size); i++ {
+					model[i] = true
+				}
+				e.Remove(size, size+8)
+			}
+
+			var want []Range
+			for i := int64(0); i < size; i++ {
+				if !model[i] {
+					continue
+				}
+				if n := len(want); n > 0 && want[n-1].End == i {
+					want[n-1].End++
+				} else {
+					want = append(want, Range{Start: i, End: i + 1})
+				}
+			}
+			if got := e.Ranges(); len(got) != len(want) || len(got) > 0 && !reflect.DeepEqual(got, want) {
+				t.Fatalf("round %d op %d: got %v, want %v", round, op, got, want)
+			}
+
+			start, end = randomRange()
+			var covered int64
+			for i := start; i < end; i++ {
+				if model[i] {
+					covered++
+				}
+			}
+			if got := e.Covered(start, end); got != covered {
+				t.Fatalf("Covered(%d, %d): got %d, want %d", start, end, got, covered)
+			}
+			if got := e.Contains(start, end); got != (covered == end-start) {
+				t.Fatalf("Contains(%d, %d): got %v with %d of %d bytes covered", start, end, got, covered, end-start)
+			}
+			var missing int64
+			for _, r := range e.Missing(start, end) {
+				missing += r.End - r.Start
+			}
+			if missing != end-start-covered {
+				t.Fatalf("Missing(%d, %d): got %d bytes, want %d", start, end, missing, end-start-covered)
+			}
+		}
+
+		data, err := json.Marshal(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded ExtentSet
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(decoded.Ranges(), e.Ranges()) {
+			t.Fatalf("got %v after a round trip of %v", decoded.Ranges(), e.Ranges())
+		}
+	}
+}
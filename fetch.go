@@ -0,0 +1,49 @@
+package httpseek
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// fetch reads up to len(p) bytes at off with a bounded range request, leaving the open stream untouched.
+// It returns io.EOF if the content ends before p is filled.
+func (s *Seeker) fetch(ctx context.Context, p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	r, err := s.fetchReader(ctx, off, int64(len(p)))
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+
+	n, err := io.ReadFull(r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// fetchReader opens a bounded range request for n bytes at off, leaving the open stream untouched.
+// It returns io.EOF if the server reports that off is at or past the end of the content.
+func (s *Seeker) fetchReader(ctx context.Context, off int64, n int64) (io.ReadCloser, error) {
+	r, size, resp, err := s.open(ctx, off, n)
+	if err != nil {
+		return nil, err
+	}
+	if resp != nil && resp.StatusCode != http.StatusOK {
+		_ = r.Close()
+		return nil, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	s.mu.Lock()
+	if s.size < 0 && size >= 0 {
+		s.setSize(size)
+	}
+	s.mu.Unlock()
+	if size >= 0 && off >= size {
+		_ = r.Close()
+		return nil, io.EOF
+	}
+	return r, nil
+}
@@ -0,0 +1,102 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+)
+
+// Fingerprint is a best-effort identity of a content for origins that send neither ETag nor Last-Modified.
+// It is a heuristic: two versions with the same size, type and first bytes are indistinguishable.
+type Fingerprint struct {
+	// Size is the total size, or -1 if unknown.
+	Size int64
+	// ContentType is the Content-Type header of the first response.
+	ContentType string
+	// PrefixLen is the number of leading bytes hashed into PrefixSum.
+	PrefixLen int
+	// PrefixSum is the SHA-256 of the first PrefixLen bytes, or nil if they were not captured yet.
+	PrefixSum []byte
+}
+
+// Fingerprint returns the fingerprint of the content as far as it is known.
+func (s *Seeker) Fingerprint() Fingerprint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return Fingerprint{
+		Size:        s.size,
+		ContentType: s.contentType,
+		PrefixLen:   s.fpLen,
+		PrefixSum:   append([]byte(nil), s.fpSum...),
+	}
+}
+
+// feedFingerprint hashes the bytes delivered at start that fall within the fingerprinted prefix.
+func (s *Seeker) feedFingerprint(start int64, p []byte, eof bool) {
+	n := s.cfg.fingerprintLen
+	if n <= 0 || s.fpSum != nil || start != int64(s.fpLen) {
+		return
+	}
+	if s.fpHash == nil {
+		s.fpHash = sha256.New()
+	}
+	take := min(len(p), n-s.fpLen)
+	s.fpHash.Write(p[:take])
+	s.mu.Lock()
+	s.fpLen += take
+	if s.fpLen == n || eof {
+		s.fpSum = s.fpHash.Sum(nil)
+		s.fpHash = nil
+	}
+	s.mu.Unlock()
+}
+
+// checkFingerprint re-fetches and hashes the fingerprinted prefix before a resume at offset,
+// when re-hashing is enabled and the server offers no validator to rely on, or the server was found to ignore If-Range.
+func (s *Seeker) checkFingerprint(ctx context.Context, offset int64) error {
+	if offset == 0 || s.cfg.fingerprintLen <= 0 {
+		return nil
+	}
+	if !s.IfRangeUnreliable() {
+		if !s.cfg.fingerprintRehash {
+			return nil
+		}
+		v := s.Validator()
+		if v.ETag != "" || v.LastModified != "" {
+			return nil
+		}
+	}
+
+	r, err := s.fetchReader(ctx, 0, int64(s.cfg.fingerprintLen))
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	bufp := getBuffer(s.cfg.fingerprintLen)
+	defer putBuffer(bufp)
+	h := sha256.New()
+	copied, err := io.CopyBuffer(h, io.LimitReader(r, int64(s.cfg.fingerprintLen)), *bufp)
+	if err != nil {
+		return err
+	}
+	n := int(copied)
+	sum := h.Sum(nil)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fpSum == nil {
+		s.fpLen, s.fpSum, s.fpHash = n, sum, nil
+		return nil
+	}
+	if n != s.fpLen || !bytes.Equal(sum, s.fpSum) {
+		return &ContentChangedError{
+			Offset: offset,
+			Field:  "Fingerprint",
+			Old:    hex.EncodeToString(s.fpSum),
+			New:    hex.EncodeToString(sum),
+		}
+	}
+	return nil
+}
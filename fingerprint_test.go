@@ -0,0 +1,51 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	ctx := context.Background()
+
+	for _, rehash := range []bool{false, true} {
+		content := []byte("Hello World!")
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+		}))
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithFingerprint(4, rehash))
+
+		if _, err := io.ReadFull(rsc, make([]byte, 6)); err != nil {
+			t.Fatal(err)
+		}
+		fp := rsc.Fingerprint()
+		if want := sha256.Sum256([]byte("Hell")); fp.PrefixLen != 4 || !bytes.Equal(fp.PrefixSum, want[:]) || fp.Size != 12 {
+			t.Fatalf("got fingerprint %+v", fp)
+		}
+
+		rsc.Release()
+		content = []byte("Jello World!")
+		got, err := io.ReadAll(rsc)
+		if rehash {
+			if !errors.Is(err, ErrNotResumable) || !errors.Is(err, ErrContentChanged) {
+				t.Fatalf("rehash: got %v, want a fingerprint change", err)
+			}
+		} else if err != nil || string(got) != "World!" {
+			t.Fatalf("got %q, %v; a change of the prefix alone is not detected without rehash", got, err)
+		}
+		rsc.Close()
+		s.Close()
+	}
+}
@@ -0,0 +1,86 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrFollowIdle is the kind of a TimeoutError returned when nothing was appended within the idle timeout of WithFollow.
+var ErrFollowIdle = errors.New("no data appended while following")
+
+// follow waits at the end of the content until more of it is available and opens the stream there.
+func (s *Seeker) follow(ctx context.Context) error {
+	_ = s.reset()
+	idleSince := time.Now()
+	for {
+		if err := sleep(ctx, s.cfg.followPoll); err != nil {
+			return err
+		}
+		grown, err := s.poll(ctx)
+		if err != nil {
+			s.suspectChange(s.offset, err)
+			return err
+		}
+		if grown {
+			return nil
+		}
+		if s.cfg.followIdle > 0 && time.Since(idleSince) >= s.cfg.followIdle {
+			return &TimeoutError{Kind: ErrFollowIdle, Timeout: s.cfg.followIdle, Offset: s.offset}
+		}
+	}
+}
+
+// poll asks for the bytes after the current offset and, if the content has grown, opens the stream there.
+// The validators of the grown content replace the old ones, as appending changes them;
+// a content that shrank or changed its validators without growing has been replaced.
+func (s *Seeker) poll(ctx context.Context) (bool, error) {
+	offset := s.offset
+	s.mu.Lock()
+	old := Validator{ETag: s.etag, LastModified: s.lastModified, Size: -1}
+	s.etag, s.lastModified = "", ""
+	s.polling = true
+	s.mu.Unlock()
+
+	r, size, resp, err := s.open(ctx, offset, -1)
+
+	s.mu.Lock()
+	s.polling = false
+	if err != nil || resp != nil {
+		s.etag, s.lastModified = old.ETag, old.LastModified
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		// A shrunk size is reported as a changed content.
+		return false, err
+	}
+	if resp == nil {
+		s.setStream(r, size, nil, offset)
+		return true, nil
+	}
+	if resp.StatusCode == http.StatusOK && offset == 0 && size > 0 {
+		// The content was empty, so the request was not ranged.
+		s.setStream(r, size, resp, offset)
+		return true, nil
+	}
+	_ = r.Close()
+	if resp.StatusCode == http.StatusOK && offset == 0 {
+		return false, nil
+	}
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		if total, ok := unsatisfiedSize(resp.Header.Get(contentRangeKey)); ok {
+			if total < offset {
+				return false, &ContentChangedError{Offset: offset, Field: "Size", Old: strconv.FormatInt(offset, 10), New: strconv.FormatInt(total, 10)}
+			}
+			current := Validator{ETag: resp.Header.Get("ETag"), LastModified: resp.Header.Get("Last-Modified"), Size: -1}
+			if field, oldValue, newValue, ok := old.mismatch(current); ok {
+				return false, &ContentChangedError{Offset: offset, Field: field, Old: oldValue, New: newValue}
+			}
+			return false, nil
+		}
+	}
+	return false, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+}
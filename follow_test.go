@@ -0,0 +1,153 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// growingFile is served like a log file that is appended to or rotated.
+type growingFile struct {
+	mut     sync.Mutex
+	content []byte
+	modTime time.Time
+}
+
+func (f *growingFile) set(content []byte) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	f.content = content
+	f.modTime = f.modTime.Add(time.Second)
+}
+
+func (f *growingFile) append(data string) {
+	f.mut.Lock()
+	content := append(bytes.Clone(f.content), data...)
+	f.mut.Unlock()
+	f.set(content)
+}
+
+func (f *growingFile) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	content, modTime := f.content, f.modTime
+	f.mut.Unlock()
+	http.ServeContent(w, r, "test.log", modTime, bytes.NewReader(content))
+}
+
+func TestFollow(t *testing.T) {
+	ctx := context.Background()
+
+	file := &growingFile{content: []byte("line 1\n"), modTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	s := httptest.NewServer(file)
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mut sync.Mutex
+	var sizes []int64
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req,
+		WithFollow(10*time.Millisecond, 300*time.Millisecond),
+		WithOnSizeKnown(func(size int64) {
+			mut.Lock()
+			defer mut.Unlock()
+			sizes = append(sizes, size)
+		}),
+	)
+	defer rsc.Close()
+
+	go func() {
+		for _, line := range []string{"line 2\n", "line 3\n"} {
+			time.Sleep(50 * time.Millisecond)
+			file.append(line)
+		}
+	}()
+
+	want := "line 1\nline 2\nline 3\n"
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(rsc, got); err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	start := time.Now()
+	_, err = rsc.Read(make([]byte, 1))
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || !errors.Is(err, ErrFollowIdle) {
+		t.Fatalf("got %v, want %v", err, ErrFollowIdle)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Fatalf("gave up after %s, want the idle timeout", elapsed)
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+	if len(sizes) != 3 || sizes[0] != 7 || sizes[2] != int64(len(want)) {
+		t.Fatalf("got sizes %v, want each growth reported", sizes)
+	}
+}
+
+func TestFollowRotated(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name    string
+		rotated []byte
+	}{
+		{name: "shrunk", rotated: []byte("new\n")},
+		{name: "same size", rotated: []byte("line 9\n")},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			file := &growingFile{content: []byte("line 1\n"), modTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+			s := httptest.NewServer(file)
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithFollow(10*time.Millisecond, time.Second))
+			defer rsc.Close()
+
+			if _, err := io.ReadFull(rsc, make([]byte, 7)); err != nil {
+				t.Fatal(err)
+			}
+			file.set(tt.rotated)
+			if _, err := rsc.Read(make([]byte, 1)); !errors.Is(err, ErrContentChanged) {
+				t.Fatalf("got %v, want %v", err, ErrContentChanged)
+			}
+		})
+	}
+}
+
+func TestFollowCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	file := &growingFile{content: []byte("line 1\n")}
+	s := httptest.NewServer(file)
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithFollow(10*time.Millisecond, 0))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 7)); err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if _, err := rsc.Read(make([]byte, 1)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,133 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// remoteFS is an fs.FS whose files are the resources under a base URL.
+type remoteFS struct {
+	ctx       context.Context
+	transport http.RoundTripper
+	base      *url.URL
+	err       error
+	opts      []Option
+}
+
+// NewFS returns an fs.FS whose Open returns a Seeker for baseURL + "/" + name, configured by opts.
+// Names are checked with fs.ValidPath, so they cannot escape the base, and escaped as URL path elements.
+// Open learns the metadata of the file as Stat does and reports 404 and 410 as fs.ErrNotExist
+// and 401 and 403 as fs.ErrPermission. Directories cannot be listed.
+func NewFS(ctx context.Context, transport http.RoundTripper, baseURL string, opts ...Option) fs.FS {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	base, err := url.Parse(baseURL)
+	return &remoteFS{
+		ctx:       ctx,
+		transport: transport,
+		base:      base,
+		err:       err,
+		opts:      opts,
+	}
+}
+
+// Open opens the named file.
+func (f *remoteFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if f.err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: f.err}
+	}
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, f.base.JoinPath(name).String(), nil)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	s := NewSeekerWithOptions(f.ctx, f.transport, req, f.opts...)
+	if _, err := s.Stat(); err != nil {
+		s.Close()
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fsError(err)}
+	}
+	return s, nil
+}
+
+// fsError maps the status of a failed request to the matching fs error, keeping err.
+func fsError(err error) error {
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	switch statusErr.StatusCode {
+	case http.StatusNotFound, http.StatusGone:
+		return fmt.Errorf("%w: %w", fs.ErrNotExist, err)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %w", fs.ErrPermission, err)
+	}
+	return err
+}
+
+// httpFileSystem is an http.FileSystem whose files are the resources under a base URL.
+type httpFileSystem struct {
+	fs *remoteFS
+}
+
+// NewHTTPFileSystem returns an http.FileSystem whose files are Seekers for the resources under baseURL,
+// as opened by the fs.FS of NewFS, so http.FileServer serves them with range requests of its own.
+// Directories cannot be listed: the files of directory names, and Readdir of any file,
+// fail with errors.ErrUnsupported.
+func NewHTTPFileSystem(ctx context.Context, transport http.RoundTripper, baseURL string, opts ...Option) http.FileSystem {
+	return &httpFileSystem{fs: NewFS(ctx, transport, baseURL, opts...).(*remoteFS)}
+}
+
+// Open opens the named file, cleaning the name like http.Dir does.
+func (h *httpFileSystem) Open(name string) (http.File, error) {
+	if name == "" || strings.HasSuffix(name, "/") {
+		return directory(name), nil
+	}
+	cleaned := path.Clean("/" + name)[1:]
+	if cleaned == "" {
+		return directory(name), nil
+	}
+	f, err := h.fs.Open(cleaned)
+	if err != nil {
+		return nil, err
+	}
+	return httpFile{f.(*Seeker)}, nil
+}
+
+// httpFile is a Seeker as an http.File.
+type httpFile struct {
+	*Seeker
+}
+
+func (f httpFile) Readdir(count int) ([]fs.FileInfo, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: f.req.URL.Redacted(), Err: errors.ErrUnsupported}
+}
+
+// directory is a directory name opened from an httpFileSystem, which cannot be listed.
+type directory string
+
+func (d directory) Close() error { return nil }
+
+func (d directory) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: string(d), Err: errors.ErrUnsupported}
+}
+
+func (d directory) Seek(int64, int) (int64, error) {
+	return 0, &fs.PathError{Op: "seek", Path: string(d), Err: errors.ErrUnsupported}
+}
+
+func (d directory) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, &fs.PathError{Op: "readdir", Path: string(d), Err: errors.ErrUnsupported}
+}
+
+func (d directory) Stat() (fs.FileInfo, error) {
+	return nil, &fs.PathError{Op: "stat", Path: string(d), Err: errors.ErrUnsupported}
+}
@@ -0,0 +1,150 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFS(t *testing.T) {
+	ctx := context.Background()
+	files := map[string]string{
+		"/base/a.txt":     "Hello World!",
+		"/base/dir/b.txt": "nested",
+		"/base/x y?.txt":  "escaped",
+		"/outside/secret": "escaped the base",
+	}
+	var paths []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/base/forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader(content))
+	}))
+	defer s.Close()
+
+	fsys := NewFS(ctx, s.Client().Transport, s.URL+"/base")
+
+	for name, want := range map[string]string{"a.txt": "Hello World!", "dir/b.txt": "nested", "x y?.txt": "escaped"} {
+		got, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			t.Fatalf("ReadFile(%q): %v", name, err)
+		}
+		if string(got) != want {
+			t.Fatalf("ReadFile(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	f, err := fsys.Open("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.(io.Seeker).Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(f); err != nil || string(got) != "World!" {
+		t.Fatalf("got %q, %v after Seek, want %q", got, err, "World!")
+	}
+	f.Close()
+
+	tests := []struct {
+		name string
+		want error
+	}{
+		{"missing.txt", fs.ErrNotExist},
+		{"forbidden", fs.ErrPermission},
+		{"../outside/secret", fs.ErrInvalid},
+		{"dir/../c", fs.ErrInvalid},
+		{"/base/a.txt", fs.ErrInvalid},
+		{".", fs.ErrInvalid},
+	}
+	for _, tt := range tests {
+		paths = nil
+		_, err := fsys.Open(tt.name)
+		var pathErr *fs.PathError
+		if !errors.Is(err, tt.want) || !errors.As(err, &pathErr) || pathErr.Path != tt.name {
+			t.Errorf("Open(%q) = %v, want a PathError for %v", tt.name, err, tt.want)
+		}
+		for _, path := range paths {
+			if !strings.HasPrefix(path, "/base/") {
+				t.Errorf("Open(%q) requested %q outside the base", tt.name, path)
+			}
+		}
+	}
+}
+
+func TestHTTPFileSystem(t *testing.T) {
+	ctx := context.Background()
+	content := "Hello World! This is served through a proxy."
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/base/file.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, "file.txt", modified, strings.NewReader(content))
+	}))
+	defer upstream.Close()
+
+	proxy := httptest.NewServer(http.FileServer(NewHTTPFileSystem(ctx, upstream.Client().Transport, upstream.URL+"/base")))
+	defer proxy.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, proxy.URL+"/file.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Range", "bytes=5-10")
+	resp, err := proxy.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusPartialContent || string(got) != content[5:11] {
+		t.Fatalf("got %d %q, want 206 %q", resp.StatusCode, got, content[5:11])
+	}
+	if want := fmt.Sprintf("bytes 5-10/%d", len(content)); resp.Header.Get("Content-Range") != want {
+		t.Fatalf("got Content-Range %q, want %q", resp.Header.Get("Content-Range"), want)
+	}
+	if want := modified.Format(http.TimeFormat); resp.Header.Get("Last-Modified") != want {
+		t.Fatalf("got Last-Modified %q, want %q", resp.Header.Get("Last-Modified"), want)
+	}
+
+	for path, want := range map[string]int{"/missing.txt": http.StatusNotFound, "/": http.StatusInternalServerError} {
+		resp, err := proxy.Client().Get(proxy.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("GET %s: got status %d, want %d", path, resp.StatusCode, want)
+		}
+	}
+
+	f, err := NewHTTPFileSystem(ctx, upstream.Client().Transport, upstream.URL+"/base").Open("/file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Readdir(0); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("got %v from Readdir, want %v", err, errors.ErrUnsupported)
+	}
+}
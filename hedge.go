@@ -0,0 +1,117 @@
+package httpseek
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+type hedgeResult struct {
+	resp  *http.Response
+	err   error
+	index int
+}
+
+// send issues req, hedging it with extra requests when it is slow to respond.
+func (s *Seeker) send(req *http.Request) (*http.Response, error) {
+	if s.cfg.hedgeDelay <= 0 || s.cfg.hedgeMaxExtra <= 0 {
+		return s.transport.RoundTrip(req)
+	}
+	return s.hedgedRoundTrip(req)
+}
+
+// hedgedRoundTrip sends req and, each time hedgeDelay passes without any response,
+// another copy of it, up to hedgeMaxExtra extra copies.
+// The first response wins; the others are canceled and their bodies closed.
+// Once every copy sent so far has failed, the last error is returned without sending another.
+func (s *Seeker) hedgedRoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	attempts := s.cfg.hedgeMaxExtra + 1
+	results := make(chan hedgeResult, attempts)
+	cancels := make([]context.CancelFunc, 0, attempts)
+
+	start := func() {
+		attemptCtx, cancel := context.WithCancel(ctx)
+		index := len(cancels)
+		cancels = append(cancels, cancel)
+		r := req.Clone(attemptCtx)
+		go func() {
+			resp, err := s.transport.RoundTrip(r)
+			results <- hedgeResult{resp: resp, err: err, index: index}
+		}()
+	}
+
+	// abandon cancels every attempt except the winner and closes the responses that still arrive.
+	abandon := func(winner, pending int) {
+		for i, cancel := range cancels {
+			if i != winner {
+				cancel()
+			}
+		}
+		go func() {
+			for ; pending > 0; pending-- {
+				res := <-results
+				if res.resp != nil {
+					_ = res.resp.Body.Close()
+				}
+			}
+		}()
+	}
+
+	start()
+	pending := 1
+	timer := time.NewTimer(s.cfg.hedgeDelay)
+	defer timer.Stop()
+
+	var lastErr error
+	for {
+		select {
+		case res := <-results:
+			pending--
+			if res.err == nil {
+				abandon(res.index, pending)
+				if res.index > 0 {
+					s.mu.Lock()
+					s.stats.HedgeWins++
+					s.mu.Unlock()
+				}
+				res.resp.Body = &cancelBody{
+					ReadCloser: res.resp.Body,
+					cancel:     cancels[res.index],
+				}
+				return res.resp, nil
+			}
+			lastErr = res.err
+			if pending == 0 {
+				// A failure is not a slow response: leave it to the retry path instead of hedging it.
+				abandon(-1, 0)
+				return nil, lastErr
+			}
+		case <-timer.C:
+			if len(cancels) < attempts {
+				s.mu.Lock()
+				s.stats.HedgedRequests++
+				s.mu.Unlock()
+				start()
+				pending++
+				timer.Reset(s.cfg.hedgeDelay)
+			}
+		case <-ctx.Done():
+			abandon(-1, pending)
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// cancelBody cancels the context of its request once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,82 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHedging(t *testing.T) {
+	ctx := context.Background()
+
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithHedging(20*time.Millisecond, 1))
+	defer rsc.Close()
+
+	start := time.Now()
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("took %s, want the hedged request to win", elapsed)
+	}
+
+	stats := rsc.Stats()
+	if stats.HedgedRequests != 1 || stats.HedgeWins != 1 {
+		t.Fatalf("got %d hedged requests and %d wins, want 1 and 1", stats.HedgedRequests, stats.HedgeWins)
+	}
+}
+
+func TestHedgingFailureNotHedged(t *testing.T) {
+	ctx := context.Background()
+
+	errRefused := errors.New("connection refused")
+	var requests atomic.Int32
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests.Add(1)
+		return nil, errRefused
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithHedging(time.Second, 2))
+	defer rsc.Close()
+
+	if _, err := io.ReadAll(rsc); !errors.Is(err, errRefused) {
+		t.Fatalf("got %v, want %v", err, errRefused)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Fatalf("got %d requests, want 1", got)
+	}
+	if stats := rsc.Stats(); stats.HedgedRequests != 0 {
+		t.Fatalf("got %d hedged requests, want 0", stats.HedgedRequests)
+	}
+}
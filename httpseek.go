@@ -4,24 +4,61 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"math"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 var (
 	contentRangeKey    = "Content-Range"
-	contentRangeRegexp = regexp.MustCompile(`bytes ([0-9]+)-([0-9]+)/([0-9]+|\\*)`)
+	contentRangeRegexp = regexp.MustCompile(`(?i)^\s*bytes\s+([0-9]+)\s*-\s*([0-9]+)\s*/\s*([0-9]+|\*)\s*$`)
+	// strictContentRangeRegexp only accepts the exact form of RFC 9110, for WithStrictContentRange.
+	strictContentRangeRegexp = regexp.MustCompile(`^bytes ([0-9]+)-([0-9]+)/([0-9]+|\*)$`)
+	unsatisfiedRangeRegexp   = regexp.MustCompile(`(?i)^\s*bytes\s+\*\s*/\s*([0-9]+)\s*$`)
 
 	// ErrCodeForByteRange is returned when the HTTP status code is not 206 for a byte range request.
 	ErrCodeForByteRange = errors.New("expected HTTP 206 from byte range request")
 
 	// ErrNoContentRange is returned when the Content-Range header is missing from a 206 response.
 	ErrNoContentRange = errors.New("no Content-Range header found in HTTP 206 response")
+
+	// ErrContentRangeParse is matched by errors.Is when the Content-Range header of a 206 response cannot be parsed.
+	// A server sending it is unlikely to send a usable one on retry.
+	ErrContentRangeParse = errors.New("could not parse Content-Range header")
+
+	// ErrRangeOffsetMismatch is matched by errors.Is when a 206 response covers a range that does not contain
+	// the requested offset. A fresh request may be answered correctly.
+	ErrRangeOffsetMismatch = errors.New("Content-Range does not cover the requested offset")
+
+	// ErrSizeMismatch is matched by errors.Is when the total size in a Content-Range is inconsistent with its range
+	// or too large.
+	ErrSizeMismatch = errors.New("Content-Range size is inconsistent")
 )
 
+// ReadError is returned when reading a response body fails before the content is complete.
+// It keeps the error reported by the body, so errors.As can still recover the transport-level cause.
+type ReadError struct {
+	// Offset is the offset at which the read failed.
+	Offset int64
+	// Err is the error reported by the body, or io.ErrUnexpectedEOF if the body ended early.
+	Err error
+}
+
+func (e *ReadError) Error() string {
+	return fmt.Sprintf("read at offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *ReadError) Unwrap() error {
+	return e.Err
+}
+
 var (
 	_ io.Seeker = (*Seeker)(nil)
 	_ io.Reader = (*Seeker)(nil)
@@ -30,12 +67,68 @@ var (
 
 // NewSeeker handles reading from an HTTP endpoint using a GET request.
 func NewSeeker(ctx context.Context, transport http.RoundTripper, req *http.Request) *Seeker {
-	return &Seeker{
-		ctx:       ctx,
-		transport: transport,
-		req:       req,
-		size:      -1,
+	return NewSeekerWithOptions(ctx, transport, req)
+}
+
+// NewSeekerFromURL handles reading from rawURL using a GET request, configured by opts.
+// A nil transport means http.DefaultTransport. Only absolute http and https URLs are accepted;
+// use NewSeeker with a prepared request for transports that handle other schemes.
+func NewSeekerFromURL(ctx context.Context, transport http.RoundTripper, rawURL string, opts ...Option) (*Seeker, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
 	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported URL scheme %q in %q", u.Scheme, u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("missing host in URL %q", u.Redacted())
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return NewSeekerWithOptions(ctx, transport, req, opts...), nil
+}
+
+// NewSeekerFromResponse adopts resp, a response to a request already sent, as the stream of a Seeker at offset 0,
+// so that its body is read without being requested again. Size, validators and the final URL are taken from resp,
+// and later resumes and seeks send a clone of resp.Request through transport, configured by opts.
+// A nil transport means http.DefaultTransport. Only a 200 response can be adopted; on error, the body of resp is closed.
+func NewSeekerFromResponse(ctx context.Context, transport http.RoundTripper, resp *http.Response, opts ...Option) (*Seeker, error) {
+	if resp.Request == nil {
+		_ = resp.Body.Close()
+		return nil, errors.New("response has no request")
+	}
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	req := resp.Request.Clone(ctx)
+	req.Header.Del("Range")
+	req.Header.Del("If-Range")
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	s := NewSeekerWithOptions(ctx, transport, req, opts...)
+
+	s.mu.Lock()
+	s.recordAcceptRanges(resp.Header)
+	err := s.checkSizeAt(0, resp, resp.ContentLength)
+	if err == nil {
+		err = s.checkResponse(0, req, resp)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		_ = resp.Body.Close()
+		s.Close()
+		return nil, err
+	}
+	s.setStream(resp.Body, resp.ContentLength, resp, 0)
+	return s, nil
 }
 
 type Seeker struct {
@@ -43,77 +136,488 @@ type Seeker struct {
 	transport     http.RoundTripper
 	req           *http.Request
 	firstResponse *http.Response
+	cfg           config
 
 	rc     io.ReadCloser
-	offset uint64
+	offset int64
 	size   int64
+
+	buffered readBuffer
+
+	contentType        string
+	contentTypeKnown   bool
+	sniffedContentType string
+
+	acceptRanges      []string
+	rangesUnsupported bool
+
+	ifRangeChecked    bool
+	ifRangeUnreliable bool
+
+	representationKnown bool
+	contentLocation     string
+	pinnedHeaders       http.Header
+
+	transferStart time.Time
+	progressed    bool
+
+	attempts       []AttemptInfo
+	currentAttempt int
+	timings        timingSamples
+
+	throughput throughputMonitor
+
+	etag         string
+	lastModified string
+	lastRequest  *http.Request
+	finalURL     *url.URL
+	lastResponse *http.Response
+	// lastResponseRequest is the request that got lastResponse.
+	lastResponseRequest *http.Request
+	// streamRequest and streamResponse are those of the attempt read by Read, to dump it if its body breaks.
+	streamRequest  *http.Request
+	streamResponse *http.Response
+
+	audit   *auditLog
+	segment segment
+
+	tail    []byte
+	tailEnd int64
+
+	prefetch *tailPrefetch
+	warm     *warmup
+	blocks   blockCache
+	limiter  *rateLimiter
+
+	cacheFill cacheFill
+
+	fpHash hash.Hash
+	fpLen  int
+	fpSum  []byte
+
+	digest    announcedDigest
+	digestSum orderedHash
+	// digestErr is the *DigestError found when the content was delivered, returned by Read at its end.
+	digestErr error
+
+	progressReported int64
+	progressCalled   bool
+
+	delivered int64
+	complete  bool
+
+	resumeFailOffset int64
+	resumeFailures   int
+	resumeCause      error
+
+	// closed is set by Close; Read, Seek and ReadAt fail with fs.ErrClosed afterwards.
+	closed atomic.Bool
+
+	// requestCount and bytesFetched are reported by RequestCount and BytesFetched.
+	requestCount atomic.Int64
+	bytesFetched atomic.Int64
+
+	// deliverMu serializes the delivery of events; events is guarded by mu.
+	deliverMu sync.Mutex
+	events    []Event
+
+	// streamMu serializes Read and Seek; a Seek interrupts a Read in flight
+	// instead of waiting for it.
+	streamMu sync.Mutex
+
+	// mu guards the state shared by the stream and concurrent ReadAt calls:
+	// the stats, the size, what was learned about the content and the attempt history.
+	// It also guards the stream context and the Read and Seek in flight.
+	mu           sync.Mutex
+	stats        Stats
+	streamCtx    context.Context
+	streamCancel context.CancelFunc
+	reading      bool
+	interrupted  bool
+	seeking      int
+	resumeLevel  ResumeLevel
+	polling      bool
+}
+
+// Read reads from the content at the current offset.
+//
+// A Seek from another goroutine aborts a Read blocked on the network: the Read returns
+// the bytes it already delivered with a nil error, or ErrInterrupted if there were none,
+// and the Seek then moves the offset. No bytes are lost or repeated; the next Read
+// continues at the offset set by the Seek.
+func (s *Seeker) Read(p []byte) (int, error) {
+	if s.closed.Load() {
+		return 0, s.closedError("read")
+	}
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	defer s.flushEvents()
+	ctx, ok := s.beginRead()
+	if !ok {
+		return 0, ErrInterrupted
+	}
+	if s.cfg.maxBytesPerRead > 0 && len(p) > s.cfg.maxBytesPerRead {
+		p = p[:s.cfg.maxBytesPerRead]
+	}
+	n, err := s.readBuffered(ctx, p)
+	if s.cfg.followPoll > 0 {
+		for n == 0 && err == io.EOF {
+			err = s.follow(ctx)
+			if err == nil {
+				n, err = s.readBuffered(ctx, p)
+			}
+		}
+		if err == io.EOF {
+			err = nil
+		}
+	}
+	if err == io.EOF {
+		err = s.verifyDigest()
+	}
+	s.reportProgress(err == io.EOF)
+	if s.endRead() {
+		_ = s.reset()
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if n > 0 {
+			return n, nil
+		}
+		return 0, ErrInterrupted
+	}
+	return n, err
 }
 
-func (s *Seeker) Read(p []byte) (n int, err error) {
+func (s *Seeker) read(ctx context.Context, p []byte) (n int, err error) {
+	if s.limiter != nil && len(p) > 0 {
+		allowed, err := s.limiter.wait(ctx, len(p))
+		if err != nil {
+			return 0, err
+		}
+		p = p[:allowed]
+	}
+	var pf *tailPrefetch
+	var chunk []byte
+	var chunkStart int64
 	if s.rc == nil {
-		err = s.seek(s.ctx, s.offset)
+		chunk, chunkStart = s.cachedChunk(s.offset)
+	}
+	if chunk == nil && s.rc == nil {
+		pf = s.prefetched()
+	}
+	if chunk == nil && pf == nil && s.rc == nil {
+		if size := s.Size(); size >= 0 && s.offset >= size {
+			// Nothing is left to request.
+			return 0, io.EOF
+		}
+		err = s.seek(ctx, s.offset)
 		if err != nil {
 			return 0, err
 		}
 	}
 
-	n, err = s.rc.Read(p)
-	s.offset += uint64(n)
-	if err != nil && int64(s.offset) < s.size {
+	start := time.Now()
+	switch {
+	case chunk != nil:
+		n, err = s.readCached(chunk, chunkStart, p)
+	case pf != nil:
+		n, err = s.readPrefetched(pf, p)
+	default:
+		n, err = s.rc.Read(p)
+	}
+	readStart := s.offset
+	s.keepDelivered(readStart, p[:n])
+	s.feedFingerprint(readStart, p[:n], err == io.EOF)
+	s.feedDigest(readStart, p[:n])
+	if chunk == nil {
+		// Bytes served from a cached chunk are not cached again.
+		s.feedCache(readStart, p[:n], err == io.EOF)
+	}
+	if s.limiter != nil {
+		s.limiter.take(n)
+	}
+	s.offset += int64(n)
+	s.mu.Lock()
+	s.stats.BytesRead += int64(n)
+	if n > 0 {
+		s.progressed = true
+	}
+	size := s.size
+	s.mu.Unlock()
+	if n > 0 {
+		s.cfg.recorder.BytesRead(n)
+	}
+	s.updateComplete(readStart, err == io.EOF)
+	if err == nil && pf == nil && chunk == nil && s.cfg.minThroughput > 0 &&
+		s.throughput.add(n, time.Since(start), s.cfg.minThroughput, s.cfg.throughputWindow) {
+		s.endSegment("too slow", ErrTooSlow)
 		_ = s.reset()
-		if err == io.EOF {
-			err = io.ErrUnexpectedEOF
+		s.resumeCause = ErrTooSlow
+		s.mu.Lock()
+		s.failAttempt(s.currentAttempt, ErrTooSlow)
+		s.stats.SlowAborts++
+		s.mu.Unlock()
+		if n == 0 {
+			return s.read(ctx, p)
 		}
+		return n, nil
+	}
+	if err != nil && err != io.EOF {
+		s.endSegment("error", err)
+		_ = s.reset()
+		s.resumeCause = err
+		s.mu.Lock()
+		s.failAttempt(s.currentAttempt, err)
+		s.dumpStreamLocked(err)
+		s.mu.Unlock()
+		return n, &ReadError{Offset: s.offset, Err: err}
+	}
+	if err != nil && s.offset < size && (s.cfg.sectionEnd == 0 || s.offset < s.cfg.sectionEnd) {
+		s.endSegment("error", io.ErrUnexpectedEOF)
+		_ = s.reset()
+		s.resumeCause = io.ErrUnexpectedEOF
+		s.mu.Lock()
+		s.failAttempt(s.currentAttempt, io.ErrUnexpectedEOF)
+		s.dumpStreamLocked(io.ErrUnexpectedEOF)
+		s.mu.Unlock()
+		return n, &ReadError{Offset: s.offset, Err: io.ErrUnexpectedEOF}
+	}
+	if err == io.EOF {
+		s.endSegment("eof", nil)
 	}
 	return n, err
 }
 
 // Seek sets the offset for the next Read to offset.
+// It does not make a request, except to learn the size for io.SeekEnd when that is not known yet;
+// errors opening the stream at the new offset are returned by the next Read.
+// As with os.File, seeking past the end is allowed: the new offset is returned, and once the size is known,
+// Reads there return io.EOF without a request. A negative offset is an error, and Seek returns
+// ErrRangeNotSupported for an offset that needs a range request once the server is known not to support
+// ranges, as it advertised Accept-Ranges: none or answered a range request with the full content.
+// Seek may be called while another goroutine is blocked in Read; see Read.
 func (s *Seeker) Seek(offset int64, whence int) (int64, error) {
+	if s.closed.Load() {
+		return 0, s.closedError("seek")
+	}
+	s.interrupt()
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	defer s.flushEvents()
+	s.endSeek()
+	// Moving the offset is not a resume of a broken stream.
+	s.resumeCause = nil
+
 	var newOffset int64
 	switch whence {
 	case io.SeekStart:
 		newOffset = offset
 	case io.SeekCurrent:
-		newOffset = int64(s.offset) + offset
+		newOffset = s.position() + offset
 	case io.SeekEnd:
-		if s.size <= 0 {
-			// TODO: make a HEAD request to get the content length
-			return 0, errors.New("content length not known")
+		s.startPrefetch()
+		s.prefetched()
+		size := s.Size()
+		if size < 0 {
+			if err := s.discoverSize(s.ctx); err != nil {
+				return 0, err
+			}
+			size = s.Size()
 		}
-		newOffset = s.size + offset
+		newOffset = size + offset
 	}
 	if newOffset < 0 {
 		return 0, errors.New("negative offset")
 	}
+	if err := s.checkSeekable(newOffset); err != nil {
+		return 0, err
+	}
 
-	return newOffset, s.seek(s.ctx, uint64(newOffset))
+	return newOffset, s.seekBuffered(newOffset)
 }
 
-func (s *Seeker) seek(ctx context.Context, offset uint64) error {
-	r, size, resp, err := reader(ctx, s.transport, s.req, offset, s.size)
+// seekTo moves the position to offset without making a request; the next Read opens the stream there.
+// An open body is kept when it is already at offset or can reach it by skipping forward, and closed otherwise.
+func (s *Seeker) seekTo(offset int64) error {
+	if offset != 0 {
+		s.closeWarmup()
+	}
+	if s.rc != nil {
+		if size := s.Size(); size >= 0 && offset > size {
+			// Nothing past the end is worth skipping to.
+			_ = s.reset()
+			s.offset = offset
+			return nil
+		}
+		if s.offset == offset || offset > s.offset && offset-s.offset <= s.cfg.skipThreshold && s.skip(offset) == nil {
+			s.mu.Lock()
+			s.stats.AvoidedRequests++
+			s.mu.Unlock()
+			return nil
+		}
+		_ = s.reset()
+	}
+	s.offset = offset
+	return nil
+}
+
+// skip moves the open body forward to offset by reading and discarding the bytes in between.
+func (s *Seeker) skip(offset int64) error {
+	s.endSegment("skipped", nil)
+	s.flushSegment()
+	n, err := discardN(s.rc, offset-s.offset)
+	s.mu.Lock()
+	s.stats.WastedBytes += int64(n)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	s.offset = offset
+	s.beginSegment(offset)
+	return nil
+}
+
+// seek opens the stream at offset, reporting it as a resume if the previous stream broke.
+func (s *Seeker) seek(ctx context.Context, offset int64) error {
+	cause := s.resumeCause
+	if cause != nil {
+		s.emit(ResumeStarted{Offset: offset, Cause: cause})
+	}
+	err := s.openStream(ctx, offset)
+	if err != nil {
+		s.suspectChange(offset, err)
+	}
+	if cause != nil {
+		s.emit(ResumeFinished{Offset: offset, Err: err})
+	}
 	if err != nil {
 		return err
 	}
+	s.resumeCause = nil
+	return nil
+}
+
+func (s *Seeker) openStream(ctx context.Context, offset int64) error {
+	s.startPrefetch()
+	s.checkIfRangeHonored(ctx, offset)
+	if err := s.checkFingerprint(ctx, offset); err != nil {
+		if isNotResumable(err) {
+			return &NotResumableError{Offset: offset, Err: err}
+		}
+		return err
+	}
+	r, size, resp, err := s.openVerified(ctx, offset)
+	for err != nil && offset > 0 && s.escalate(err) {
+		r, size, resp, err = s.openVerified(ctx, offset)
+	}
+	if s.cfg.encodedRangeFallback && errors.Is(err, ErrEncodedRange) {
+		r, size, resp, err = s.decodeFromStart(ctx, offset)
+	}
+	if err != nil {
+		if offset > 0 && isNotResumable(err) {
+			return &NotResumableError{Offset: offset, Err: err}
+		}
+		if !s.resumeFailed(offset) {
+			return err
+		}
+		r, size, resp, err = s.restart(ctx, offset, err)
+		if err != nil {
+			return err
+		}
+	}
+	s.resumeFailures = 0
+	s.setStream(r, size, resp, offset)
+	return nil
+}
+
+// setStream makes r, opened at offset, the stream read by Read.
+func (s *Seeker) setStream(r io.ReadCloser, size int64, resp *http.Response, offset int64) {
 	_ = s.reset()
-	if offset == 0 {
+	s.mu.Lock()
+	if s.firstResponse == nil && resp != nil {
 		s.firstResponse = resp
 	}
-	s.size = size
+	if size >= 0 {
+		// A response of unknown length, such as a chunked one, keeps the size learned before.
+		s.setSize(size)
+	}
+	s.currentAttempt = s.stats.Requests
+	s.streamRequest, s.streamResponse = s.lastResponseRequest, s.lastResponse
+	s.mu.Unlock()
 	s.offset = offset
-	s.rc = r
-	return nil
+	s.rc = s.readAhead(r)
+	s.cacheFill.buf = nil
+	s.throughput.reset()
+	s.beginSegment(offset)
 }
 
-// Close closes the Seeker.
+// openVerified opens the stream at offset, starting early enough to verify the overlap with the bytes already delivered.
+func (s *Seeker) openVerified(ctx context.Context, offset int64) (io.ReadCloser, int64, *http.Response, error) {
+	overlap := s.overlapBefore(offset)
+	var r io.ReadCloser
+	var size int64
+	var resp *http.Response
+	var err error
+	if w := s.takeWarmup(offset - int64(overlap)); w != nil {
+		r, size, resp, err = w.r, w.size, w.resp, w.err
+	} else {
+		r, size, resp, err = s.open(ctx, offset-int64(overlap), s.streamLength(offset-int64(overlap)))
+	}
+	if err == nil && overlap > 0 && (resp == nil || resp.StatusCode == http.StatusOK) {
+		err = s.verifyOverlap(r, offset, overlap)
+		if err != nil {
+			_ = r.Close()
+			return nil, -1, nil, err
+		}
+	}
+	return r, size, resp, err
+}
+
+// Close closes the Seeker. Read, Peek, Discard, Seek and ReadAt then fail with an error wrapping fs.ErrClosed.
 func (s *Seeker) Close() error {
+	s.closed.Store(true)
+	defer s.flushEvents()
+	s.closeWarmup()
+	s.closePrefetch()
+	s.blocks.close()
+	s.releaseDelivered()
+	err := s.reset()
+	s.cancelStream()
+	s.closeAudit()
+	return err
+}
+
+// Release closes the open response body, freeing its connection, while keeping the offset,
+// size and everything learned about the content. The next Read resumes at the same offset with a new request.
+// Release must not be called concurrently with Read and is a no-op when no body is open.
+func (s *Seeker) Release() error {
+	if s.rc == nil {
+		return nil
+	}
+	s.mu.Lock()
+	s.stats.Releases++
+	s.mu.Unlock()
 	return s.reset()
 }
 
+// Detach rebinds the Seeker to a context that keeps the values of the construction context
+// but is never canceled with it, so the Seeker can be handed to background work that outlives
+// the caller, such as an HTTP handler. A body opened under the old context is closed, since it
+// would fail once that context ends; the next Read resumes at the same offset. Detach returns s.
+// Detach must not be called concurrently with Read.
+func (s *Seeker) Detach() *Seeker {
+	s.ctx = context.WithoutCancel(s.ctx)
+	s.closeWarmup()
+	_ = s.reset()
+	s.cancelStream()
+	return s
+}
+
 // Response returns the first HTTP response received from the server.
 func (s *Seeker) Response() (*http.Response, error) {
+	defer s.flushEvents()
 	if s.firstResponse == nil {
-		err := s.seek(s.ctx, 0)
+		err := s.seek(s.streamContext(), 0)
 		if err != nil {
 			return nil, err
 		}
@@ -123,12 +627,14 @@ func (s *Seeker) Response() (*http.Response, error) {
 
 // Size returns the content length of the HTTP response.
 func (s *Seeker) Size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.size
 }
 
 // Offset returns the current offset of the Seeker.
-func (s *Seeker) Offset() uint64 {
-	return s.offset
+func (s *Seeker) Offset() int64 {
+	return s.position()
 }
 
 func (s *Seeker) reset() error {
@@ -137,81 +643,327 @@ func (s *Seeker) reset() error {
 	}
 	err := s.rc.Close()
 	s.rc = nil
+	s.flushSegment()
 	return err
 }
 
-func reader(ctx context.Context, transport http.RoundTripper, req *http.Request, readerOffset uint64, readerSize int64) (io.ReadCloser, int64, *http.Response, error) {
-	req = req.Clone(ctx)
-	if readerOffset > 0 {
+// reader requests length bytes of the content starting at readerOffset, or the rest of it if length is negative.
+func (s *Seeker) reader(ctx context.Context, readerOffset int64, length int64) (io.ReadCloser, int64, *http.Response, error) {
+	ctx, timer := s.cfg.startAttempt(ctx)
+	traceCtx, trace := withTrace(ctx)
+	req := s.req.Clone(traceCtx)
+	if length >= 0 {
+		req.Header.Add("Range", fmt.Sprintf("bytes=%d-%d", readerOffset, readerOffset+length-1))
+	} else if readerOffset > 0 {
 		req.Header.Add("Range", fmt.Sprintf("bytes=%d-", readerOffset))
 	}
-
-	resp, err := transport.RoundTrip(req)
+	s.mu.Lock()
+	s.pinHeaders(req)
+	s.setIfRange(req)
+	level := s.applyResumeLevel(req, readerOffset)
+	s.stats.Requests++
+	attempt := s.stats.Requests
+	s.mu.Unlock()
+
+	resp, req, chain, err := s.roundTrip(req)
+	resp, err = timer.stop(readerOffset, resp, err)
+	timing := trace.finish()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if trace != nil {
+		s.timings.add(timing)
+	}
 	if err != nil {
+		s.recordAttempt(AttemptInfo{Attempt: attempt, Offset: readerOffset, Ranged: readerOffset > 0, Level: level, Err: err, Hops: chain, Timing: timing})
+		s.cfg.debugDump.dump(attempt, req, nil, err)
 		return nil, -1, nil, err
 	}
+	s.recordAttempt(AttemptInfo{Attempt: attempt, Offset: readerOffset, Ranged: readerOffset > 0, Level: level, StatusCode: resp.StatusCode, Hops: chain, Timing: timing})
+	s.finalURL = req.URL
+	s.lastResponse = resp
+	s.lastResponseRequest = req
+	if length < 0 {
+		s.lastRequest = req
+	}
+
+	code := resp.StatusCode
+	if action, ok := s.cfg.statusActions[code]; ok {
+		switch action {
+		case StatusTreatAsOK:
+			code = http.StatusOK
+		case StatusRetryAfterDelay:
+			code = 0
+			err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, retry: true}
+		case StatusFail:
+			code = 0
+			err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+		case StatusPassthrough:
+			code = -1
+		}
+	}
 
-	switch resp.StatusCode {
+	switch code {
+	case 0:
+		// failed by the status table
 	case http.StatusOK, http.StatusNoContent:
-		if readerOffset == 0 {
-			return resp.Body, resp.ContentLength, resp, nil
+		s.recordAcceptRanges(resp.Header)
+		err = s.checkIfRange(readerOffset, req, resp)
+		if err != nil {
+			break
+		}
+		size := resp.ContentLength
+		if encoding := contentEncoding(resp.Header); encoding != "" {
+			if readerOffset != 0 || length >= 0 {
+				err = &EncodedRangeError{Offset: readerOffset, Encoding: encoding}
+				break
+			}
+			// The length of the encoded body is not the size of the content.
+			size = -1
+		}
+		if readerOffset != 0 {
+			// The full content in answer to a range request confirms that ranges are not supported.
+			s.rangesUnsupported = true
+			if readerOffset > s.cfg.rangeFallback {
+				err = ErrCodeForByteRange
+				break
+			}
+			return s.rangeFallback(readerOffset, length, req, resp)
+		}
+		err = s.checkSizeAt(readerOffset, resp, size)
+		if err != nil {
+			break
+		}
+		err = s.checkResponse(readerOffset, req, resp)
+		if err == nil {
+			return resp.Body, size, resp, nil
 		}
-		return nil, -1, nil, ErrCodeForByteRange
 	case http.StatusPartialContent:
+		s.recordAcceptRanges(resp.Header)
+		if encoding := contentEncoding(resp.Header); encoding != "" {
+			err = &EncodedRangeError{Offset: readerOffset, Encoding: encoding}
+			break
+		}
 		contentRange := resp.Header.Get(contentRangeKey)
 		if contentRange == "" {
-			return nil, -1, nil, ErrNoContentRange
+			err = ErrNoContentRange
+			break
 		}
 
-		s, err := getContentLength(contentRange, readerOffset, readerSize)
+		var start, end int64
+		var size int64
+		start, end, size, err = getContentLength(contentRange, readerOffset, length, s.cfg.strictContentRange)
 		if err != nil {
-			return nil, -1, nil, err
+			break
+		}
+		if end < readerOffset {
+			err = fmt.Errorf("%w: range ends before the requested offset %d: %s", ErrRangeOffsetMismatch, readerOffset, contentRange)
+			break
+		}
+		err = s.checkSizeAt(readerOffset, resp, size)
+		if err != nil {
+			break
 		}
-		return resp.Body, s, nil, nil
+		err = s.checkResponse(readerOffset, req, resp)
+		if err != nil {
+			break
+		}
+		if start < readerOffset {
+			s.stats.WastedBytes += int64(readerOffset - start)
+			err = discard(resp.Body, readerOffset-start)
+			if err != nil {
+				break
+			}
+		}
+		if (length >= 0 || size >= 0) && end+1 < requestedEnd(readerOffset, length, size) {
+			return s.newWindowBody(ctx, resp.Body, readerOffset, end+1, length), size, nil, nil
+		}
+		return resp.Body, size, nil, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		total, ok := unsatisfiedSize(resp.Header.Get(contentRangeKey))
+		if !ok || readerOffset < total || s.polling {
+			s.cfg.debugDump.dump(attempt, req, resp, nil)
+			return resp.Body, -1, resp, nil
+		}
+		// The offset is at or past the end, which is where the content ends.
+		err = s.checkSizeAt(readerOffset, resp, total)
+		if err != nil {
+			break
+		}
+		s.cfg.debugDump.dump(attempt, req, resp, nil)
+		_ = resp.Body.Close()
+		return http.NoBody, total, nil, nil
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if s.cfg.serverErrorRetries <= 0 {
+			s.cfg.debugDump.dump(attempt, req, resp, nil)
+			return resp.Body, -1, resp, nil
+		}
+		err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, serverError: true}
+	case http.StatusPreconditionFailed:
+		if level < ResumeValidated {
+			s.cfg.debugDump.dump(attempt, req, resp, nil)
+			return resp.Body, -1, resp, nil
+		}
+		// The validators sent by ResumeValidated no longer match.
+		changed := &ContentChangedError{Offset: readerOffset, Field: "ETag", Old: s.etag, New: resp.Header.Get("ETag")}
+		if s.etag == "" {
+			changed = &ContentChangedError{Offset: readerOffset, Field: "Last-Modified", Old: s.lastModified, New: resp.Header.Get("Last-Modified")}
+		}
+		err = s.changedBy(changed, resp, -1)
+	default:
+		s.cfg.debugDump.dump(attempt, req, resp, nil)
+		return resp.Body, -1, resp, nil
 	}
 
-	return resp.Body, -1, resp, nil
+	s.failAttempt(attempt, err)
+	s.cfg.debugDump.dump(attempt, req, resp, err)
+	if classifyRetry(err) == RetryServerError {
+		// Drain the error page so the connection can be reused by the retry.
+		drainBody(resp)
+	} else {
+		_ = resp.Body.Close()
+	}
+	return nil, -1, nil, err
 }
 
-func getContentLength(contentRange string, readerOffset uint64, readerSize int64) (int64, error) {
-	submatches := contentRangeRegexp.FindStringSubmatch(contentRange)
-	if len(submatches) < 4 {
-		return 0, fmt.Errorf("could not parse Content-Range header: %s", contentRange)
+// rangeFallback serves a range request answered with the full content by discarding the bytes before readerOffset,
+// as enabled by WithRangeFallback. It must be called with s.mu held.
+func (s *Seeker) rangeFallback(readerOffset int64, length int64, req *http.Request, resp *http.Response) (io.ReadCloser, int64, *http.Response, error) {
+	err := s.checkSizeAt(readerOffset, resp, resp.ContentLength)
+	if err == nil {
+		err = s.checkResponse(readerOffset, req, resp)
 	}
+	if err == nil {
+		s.stats.WastedBytes += readerOffset
+		err = discard(resp.Body, readerOffset)
+	}
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, -1, nil, err
+	}
+	body := resp.Body
+	if length >= 0 {
+		body = struct {
+			io.Reader
+			io.Closer
+		}{
+			Reader: io.LimitReader(resp.Body, length),
+			Closer: resp.Body,
+		}
+	}
+	return body, resp.ContentLength, nil, nil
+}
+
+// checkResponse compares a successful response with what earlier responses described.
+func (s *Seeker) checkResponse(offset int64, req *http.Request, resp *http.Response) error {
+	return s.checkResponseAs(offset, req, resp, resp.Header.Get("Content-Type"))
+}
 
-	startByte, err := strconv.ParseUint(submatches[1], 10, 64)
+// checkResponseAs is checkResponse for a response whose content has the given Content-Type,
+// which differs from the one of the response for multipart responses.
+func (s *Seeker) checkResponseAs(offset int64, req *http.Request, resp *http.Response, contentType string) error {
+	if s.etag == "" && s.lastModified == "" {
+		s.etag = resp.Header.Get("ETag")
+		s.lastModified = resp.Header.Get("Last-Modified")
+		if s.etag != "" || s.lastModified != "" {
+			s.emitLocked(ValidatorsDiscovered{ETag: s.etag, LastModified: s.lastModified})
+		}
+		if s.cfg.validatorPolicy == ValidatorRequired && s.ifRangeValidator() == "" {
+			return ErrNoValidator
+		}
+	}
+	s.recordDigest(resp)
+	err := s.checkContentType(offset, contentType)
+	if err != nil {
+		return err
+	}
+	return s.checkRepresentation(offset, req, resp)
+}
+
+// getContentLength parses the Content-Range of a response to a request for length bytes at readerOffset,
+// or the rest of the content if length is negative, and returns the first and last byte of the returned range
+// and the total size, or -1 if the size is unknown.
+// The range may start before readerOffset, as some servers round starts down to block boundaries,
+// and may end before the requested end, as some servers cap the size of a range response.
+// Unless strict, the unit is matched case-insensitively and whitespace around the values is ignored.
+func getContentLength(contentRange string, readerOffset int64, length int64, strict bool) (int64, int64, int64, error) {
+	startByte, endByte, size, err := parseContentRange(contentRange, strict)
 	if err != nil {
-		return 0, fmt.Errorf("could not parse start of range in Content-Range header: %s", contentRange)
+		return 0, 0, 0, err
+	}
+	if startByte > readerOffset {
+		return 0, 0, 0, fmt.Errorf("%w: range starts at offset %d instead of requested %d", ErrRangeOffsetMismatch, startByte, readerOffset)
+	}
+	return startByte, endByte, size, nil
+}
+
+// parseContentRange returns the first byte, the last byte and the total size, or -1 if unknown, of a Content-Range.
+func parseContentRange(contentRange string, strict bool) (int64, int64, int64, error) {
+	re := contentRangeRegexp
+	if strict {
+		re = strictContentRangeRegexp
+	}
+	submatches := re.FindStringSubmatch(contentRange)
+	if len(submatches) < 4 {
+		return 0, 0, 0, fmt.Errorf("%w: %s", ErrContentRangeParse, contentRange)
 	}
 
-	if startByte != readerOffset {
-		return 0, fmt.Errorf("received Content-Range starting at offset %d instead of requested %d", startByte, readerOffset)
+	startByte, err := strconv.ParseInt(submatches[1], 10, 64)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("%w: invalid start of range: %s", ErrContentRangeParse, contentRange)
 	}
 
-	endByte, err := strconv.ParseUint(submatches[2], 10, 64)
+	endByte, err := strconv.ParseInt(submatches[2], 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("could not parse end of range in Content-Range header: %s", contentRange)
+		return 0, 0, 0, fmt.Errorf("%w: invalid end of range: %s", ErrContentRangeParse, contentRange)
 	}
 
 	if submatches[3] == "*" {
-		return -1, nil
+		return startByte, endByte, -1, nil
 	}
 
 	size, err := strconv.ParseUint(submatches[3], 10, 64)
 	if err != nil {
-		return 0, fmt.Errorf("could not parse total size in Content-Range header: %s", contentRange)
+		return 0, 0, 0, fmt.Errorf("%w: invalid total size: %s", ErrContentRangeParse, contentRange)
 	}
 
-	if endByte+1 != size {
-		return 0, fmt.Errorf("range in Content-Range stops before the end of the content: %s", contentRange)
+	if size > math.MaxInt64 {
+		return 0, 0, 0, fmt.Errorf("%w: size %d exceeds the maximum size", ErrSizeMismatch, size)
 	}
 
-	if readerOffset > 0 && size != uint64(readerSize) {
-		return 0, fmt.Errorf("Content-Range size: %d does not match expected size: %d", size, readerSize)
+	if endByte >= int64(size) {
+		return 0, 0, 0, fmt.Errorf("%w: range ends past the end of the content: %s", ErrSizeMismatch, contentRange)
 	}
+	return startByte, endByte, int64(size), nil
+}
 
-	if size > math.MaxInt64 {
-		return 0, fmt.Errorf("Content-Range size: %d exceeds max allowed size", size)
+// requestedEnd returns the offset after the last byte requested by length bytes at offset,
+// or the rest of the content if length is negative, within a content of size bytes if it is known.
+func requestedEnd(offset int64, length int64, size int64) int64 {
+	end := int64(math.MaxInt64)
+	if length >= 0 {
+		end = offset + length
+	}
+	if size >= 0 {
+		end = min(end, size)
+	}
+	return end
+}
+
+// unsatisfiedSize returns the total size in a Content-Range of the form "bytes */size", sent with
+// 416 Range Not Satisfiable, or in "bytes 0--1/0", which net/http sends for a suffix range of an empty content.
+func unsatisfiedSize(contentRange string) (int64, bool) {
+	if contentRange == "bytes 0--1/0" {
+		return 0, true
+	}
+	submatches := unsatisfiedRangeRegexp.FindStringSubmatch(contentRange)
+	if submatches == nil {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(submatches[1], 10, 64)
+	if err != nil || size < 0 {
+		return 0, false
 	}
-	return int64(size), nil
+	return size, true
 }
@@ -3,10 +3,19 @@ package httpseek
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"testing"
+	"testing/iotest"
 	"time"
 )
 
@@ -51,3 +60,659 @@ func TestSeek(t *testing.T) {
 		t.Fatalf("got %q, want %q", got, "World!")
 	}
 }
+
+func TestReadErrorCause(t *testing.T) {
+	ctx := context.Background()
+
+	cause := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: 12,
+			Body:          io.NopCloser(io.MultiReader(strings.NewReader("Hello"), iotest.ErrReader(cause))),
+			Request:       r,
+		}, nil
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, transport, req)
+	defer rsc.Close()
+
+	_, err = io.ReadAll(rsc)
+	var readErr *ReadError
+	if !errors.As(err, &readErr) {
+		t.Fatalf("got %v, want ReadError", err)
+	}
+	if readErr.Offset != 5 {
+		t.Fatalf("got offset %d, want %d", readErr.Offset, 5)
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) || opErr != cause {
+		t.Fatalf("got %v, want %v", err, cause)
+	}
+}
+
+type roundTripperFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestSeekRangeStartsEarlier(t *testing.T) {
+	ctx := context.Background()
+
+	content := []byte("Hello World!")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start); err != nil {
+			w.Write(content)
+			return
+		}
+		// Round the start down to a 4 byte block, like some servers do.
+		start &^= 3
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(content[start:])
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+	if rsc.Stats().WastedBytes != 2 {
+		t.Fatalf("got %d wasted bytes, want %d", rsc.Stats().WastedBytes, 2)
+	}
+}
+
+func TestDetach(t *testing.T) {
+	type key struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "trace"))
+	defer cancel()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if got := r.Context().Value(key{}); got != "trace" {
+			t.Errorf("context value: got %v, want trace", got)
+		}
+		return s.Client().Transport.RoundTrip(r)
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, transport, req)
+	defer rsc.Close()
+
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(rsc, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	rsc.Detach()
+	cancel()
+
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf) + string(rest); got != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+}
+
+func TestSeekIsLazy(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	for _, off := range []int64{9, 3, 11, 6} {
+		if _, err := rsc.Seek(off, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := rsc.Seek(-2, io.SeekCurrent); err != nil {
+		t.Fatal(err)
+	}
+	if got := rsc.Stats().Requests; got != 0 {
+		t.Fatalf("got %d requests before Read, want none", got)
+	}
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "o World!" {
+		t.Fatalf("got %q, want %q", got, "o World!")
+	}
+	if got := rsc.Stats().Requests; got != 1 {
+		t.Fatalf("got %d requests, want %d", got, 1)
+	}
+}
+
+func TestSeekCurrentOffsetSingleRequest(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	var requests int
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		return s.Client().Transport.RoundTrip(r)
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, transport, req)
+	defer rsc.Close()
+
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(rsc, buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(int64(rsc.Offset()), io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf) + string(rest); got != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if requests != 1 {
+		t.Fatalf("got %d requests, want %d", requests, 1)
+	}
+}
+
+func TestSkipThreshold(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	var requests int
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		return s.Client().Transport.RoundTrip(r)
+	})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithSkipThreshold(4))
+	defer rsc.Close()
+
+	var got []byte
+	read := func(n int) {
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(rsc, buf); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, buf...)
+	}
+
+	read(2)
+	if _, err := rsc.Seek(3, io.SeekCurrent); err != nil {
+		t.Fatal(err)
+	}
+	read(1)
+	if requests != 1 {
+		t.Fatalf("got %d requests after a short skip, want %d", requests, 1)
+	}
+	if _, err := rsc.Seek(11, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	read(1)
+	if _, err := rsc.Seek(-4, io.SeekCurrent); err != nil {
+		t.Fatal(err)
+	}
+	read(1)
+
+	if string(got) != "He !r" {
+		t.Fatalf("got %q, want %q", got, "He !r")
+	}
+	if requests != 3 {
+		t.Fatalf("got %d requests, want %d", requests, 3)
+	}
+	if stats := rsc.Stats(); stats.WastedBytes != 3 {
+		t.Fatalf("got %d wasted bytes, want %d", stats.WastedBytes, 3)
+	}
+}
+
+func TestNewSeekerFromURL(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	rsc, err := NewSeekerFromURL(ctx, nil, s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsc.Close()
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+
+	for _, rawURL := range []string{"ftp://example.com/file", "/relative/path", "http://", "http://a b.com/"} {
+		if _, err := NewSeekerFromURL(ctx, nil, rawURL); err == nil {
+			t.Fatalf("%q: expected an error", rawURL)
+		}
+	}
+}
+
+func TestRangeFallback(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Ignores Range.
+		w.Header().Set("Content-Length", "12")
+		w.Write([]byte("Hello World!"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithRangeFallback(8))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+	if wasted := rsc.Stats().WastedBytes; wasted != 6 {
+		t.Fatalf("got %d wasted bytes, want 6", wasted)
+	}
+
+	buf := make([]byte, 4)
+	if _, err := rsc.ReadAt(buf, 3); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "lo W" {
+		t.Fatalf("got %q, want %q", buf, "lo W")
+	}
+
+	// The full content in answer to the range request showed that ranges are not supported.
+	if _, err := rsc.Seek(10, io.SeekStart); !errors.Is(err, ErrRangeNotSupported) {
+		t.Fatalf("got %v past the discard limit, want %v", err, ErrRangeNotSupported)
+	}
+}
+
+func TestNewSeekerFromResponse(t *testing.T) {
+	ctx := context.Background()
+
+	var ranges []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	resp, err := s.Client().Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cause := errors.New("connection lost")
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(io.LimitReader(resp.Body, 5), iotest.ErrReader(cause)),
+		Closer: resp.Body,
+	}
+
+	rsc, err := NewSeekerFromResponse(ctx, s.Client().Transport, resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsc.Close()
+	if rsc.Size() != 12 {
+		t.Fatalf("got size %d, want %d", rsc.Size(), 12)
+	}
+
+	got, err := io.ReadAll(rsc)
+	if !errors.Is(err, cause) {
+		t.Fatalf("got %v, want %v", err, cause)
+	}
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, rest...)
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes=5-" {
+		t.Fatalf("got ranges %q, want the resume at %q", ranges, "bytes=5-")
+	}
+	if rsc.IfRangeValidator() != `"v1"` {
+		t.Fatalf("got If-Range validator %q, want %q", rsc.IfRangeValidator(), `"v1"`)
+	}
+
+	resp, err = s.Client().Get(s.URL + "/missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.StatusCode = http.StatusNotFound
+	if _, err := NewSeekerFromResponse(ctx, s.Client().Transport, resp); err == nil {
+		t.Fatal("expected an error for a 404 response")
+	}
+}
+
+func TestRangeNotSatisfiable(t *testing.T) {
+	ctx := context.Background()
+
+	const content = "Hello World!"
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start int
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start); err == nil {
+			if start >= len(content) {
+				w.Header().Set("Content-Range", fmt.Sprintf("bytes */%d", len(content)))
+				w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/*", start, len(content)-1))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		// Flushing first hides the size of the content.
+		w.(http.Flusher).Flush()
+		io.WriteString(w, content[start:])
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := rsc.Seek(20, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := rsc.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatalf("got %d, %v past the end, want 0, %v", n, err, io.EOF)
+	}
+	if rsc.Size() != int64(len(content)) {
+		t.Fatalf("got size %d, want %d", rsc.Size(), len(content))
+	}
+
+	if _, err := rsc.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	if _, err := rsc.Seek(0, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := rsc.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+		t.Fatalf("got %d, %v at the end, want 0, %v", n, err, io.EOF)
+	}
+}
+
+func TestGetContentLength(t *testing.T) {
+	for _, test := range []struct {
+		contentRange string
+		strict       bool
+		start, end   int64
+		size         int64
+		err          error
+	}{
+		{contentRange: "bytes 0-11/12", start: 0, end: 11, size: 12},
+		{contentRange: "bytes 0-11/12", strict: true, start: 0, end: 11, size: 12},
+		{contentRange: "bytes 0-5/*", start: 0, end: 5, size: -1},
+		{contentRange: "bytes 0-5/*", strict: true, start: 0, end: 5, size: -1},
+		{contentRange: "Bytes 0-11/12", start: 0, end: 11, size: 12},
+		{contentRange: "BYTES 0-11/12", start: 0, end: 11, size: 12},
+		{contentRange: "  bytes   0 - 11 / 12  ", start: 0, end: 11, size: 12},
+		{contentRange: "bytes 000-011/0012", start: 0, end: 11, size: 12},
+		{contentRange: "Bytes 0-11/12", strict: true, err: ErrContentRangeParse},
+		{contentRange: " bytes 0-11/12", strict: true, err: ErrContentRangeParse},
+		{contentRange: "bytes 0 - 11/12", strict: true, err: ErrContentRangeParse},
+		{contentRange: "xbytes 0-1/2", err: ErrContentRangeParse},
+		{contentRange: "bytes 0-1/2y", err: ErrContentRangeParse},
+		{contentRange: "bytes 0-1/2 bytes 0-1/2", err: ErrContentRangeParse},
+		{contentRange: "bytes */12", err: ErrContentRangeParse},
+		{contentRange: "bytes 0-12/12", err: ErrSizeMismatch},
+		{contentRange: "bytes 0-1/99999999999999999999", err: ErrContentRangeParse},
+		{contentRange: "bytes 0-1/9223372036854775808", err: ErrSizeMismatch},
+		{contentRange: "bytes 5-11/12", err: ErrRangeOffsetMismatch},
+		{contentRange: "items 0-1/2", err: ErrContentRangeParse},
+		{contentRange: "", err: ErrContentRangeParse},
+	} {
+		start, end, size, err := getContentLength(test.contentRange, 0, -1, test.strict)
+		if test.err != nil {
+			if !errors.Is(err, test.err) {
+				t.Errorf("%q (strict %v): got %v, want %v", test.contentRange, test.strict, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q (strict %v): %v", test.contentRange, test.strict, err)
+			continue
+		}
+		if start != test.start || end != test.end || size != test.size {
+			t.Errorf("%q (strict %v): got %d-%d/%d, want %d-%d/%d", test.contentRange, test.strict, start, end, size, test.start, test.end, test.size)
+		}
+	}
+}
+
+func TestUnsatisfiedSize(t *testing.T) {
+	for _, test := range []struct {
+		contentRange string
+		size         int64
+		ok           bool
+	}{
+		{contentRange: "bytes */12", size: 12, ok: true},
+		{contentRange: "Bytes  * / 12 ", size: 12, ok: true},
+		{contentRange: "bytes 0--1/0", size: 0, ok: true},
+		{contentRange: "bytes */", ok: false},
+		{contentRange: "xbytes */12", ok: false},
+		{contentRange: "bytes 0-1/12", ok: false},
+	} {
+		size, ok := unsatisfiedSize(test.contentRange)
+		if ok != test.ok || size != test.size {
+			t.Errorf("%q: got %d, %v, want %d, %v", test.contentRange, size, ok, test.size, test.ok)
+		}
+	}
+}
+
+func TestMaxBytesPerRead(t *testing.T) {
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("Hello World!"), 100)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithMaxBytesPerRead(100))
+	defer rsc.Close()
+
+	buf := make([]byte, len(content))
+	n, err := rsc.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n > 100 {
+		t.Fatalf("got %d bytes from one Read, want at most %d", n, 100)
+	}
+	if _, err := io.ReadFull(rsc, buf[n:]); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf, content) {
+		t.Fatal("got different content")
+	}
+}
+
+func TestSeekPastEnd(t *testing.T) {
+	ctx := context.Background()
+
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithSkipThreshold(1<<10))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 5)); err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		offset int64
+		whence int
+		want   int64
+	}{
+		{offset: 100, whence: io.SeekStart, want: 100},
+		{offset: 50, whence: io.SeekCurrent, want: 150},
+		{offset: 10, whence: io.SeekEnd, want: 22},
+		{offset: 0, whence: io.SeekEnd, want: 12},
+	} {
+		got, err := rsc.Seek(test.offset, test.whence)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Fatalf("Seek(%d, %d): got %d, want %d", test.offset, test.whence, got, test.want)
+		}
+		if n, err := rsc.Read(make([]byte, 1)); n != 0 || err != io.EOF {
+			t.Fatalf("Read at %d: got %d, %v, want 0, %v", got, n, err, io.EOF)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("got %d requests, want only the first", n)
+	}
+
+	if _, err := rsc.Seek(-13, io.SeekEnd); err == nil {
+		t.Fatal("expected an error for a negative offset")
+	}
+}
+
+func TestIdentityEncoding(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var encodings []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		encodings = append(encodings, r.Header.Get("Accept-Encoding"))
+		mu.Unlock()
+		if r.URL.Path != "/final" {
+			http.Redirect(w, r, "/final", http.StatusFound)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	tests := []struct {
+		enabled bool
+		want    []string
+	}{
+		{true, []string{"identity", "identity", "identity", "identity"}},
+		{false, []string{"gzip", "gzip", "", ""}},
+	}
+	for _, tt := range tests {
+		encodings = nil
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/start", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithIdentityEncoding(tt.enabled))
+
+		if _, err := io.ReadFull(rsc, make([]byte, 2)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(rsc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "World!" {
+			t.Fatalf("got %q, want %q", got, "World!")
+		}
+		rsc.Close()
+		if !reflect.DeepEqual(encodings, tt.want) {
+			t.Errorf("enabled=%t: got Accept-Encoding %q, want %q", tt.enabled, encodings, tt.want)
+		}
+	}
+}
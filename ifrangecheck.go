@@ -0,0 +1,76 @@
+package httpseek
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// defaultCheckFingerprint is the prefix fingerprinted under WithIfRangeCheck when WithFingerprint is not used.
+const defaultCheckFingerprint = 4 << 10
+
+const (
+	staleETag         = `"httpseek-stale-validator"`
+	staleLastModified = "Thu, 01 Jan 1970 00:00:00 GMT"
+)
+
+// IfRangeUnreliable reports whether the server answered a range request conditional on a stale validator
+// with the range instead of the full content, so that If-Range cannot detect a changed content.
+// It is only checked under WithIfRangeCheck.
+func (s *Seeker) IfRangeUnreliable() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ifRangeUnreliable
+}
+
+// checkIfRangeHonored sends the first byte request with a stale If-Range before the first resume at offset,
+// under WithIfRangeCheck, and records whether the server ignored it.
+// A failed check is repeated at the next resume; it never fails the resume itself.
+func (s *Seeker) checkIfRangeHonored(ctx context.Context, offset int64) {
+	if offset == 0 || !s.cfg.ifRangeCheck {
+		return
+	}
+	s.mu.Lock()
+	v := s.ifRangeValidator()
+	if s.ifRangeChecked || v == "" {
+		s.mu.Unlock()
+		return
+	}
+	traceCtx, trace := withTrace(ctx)
+	req := s.req.Clone(traceCtx)
+	req.Header.Set("Range", "bytes=0-0")
+	if strings.HasPrefix(v, `"`) {
+		req.Header.Set("If-Range", staleETag)
+	} else {
+		req.Header.Set("If-Range", staleLastModified)
+	}
+	s.pinHeaders(req)
+	s.stats.Requests++
+	s.stats.IfRangeChecks++
+	attempt := s.stats.Requests
+	s.mu.Unlock()
+
+	resp, req, chain, err := s.roundTrip(req)
+	timing := trace.finish()
+	if err == nil {
+		drainBody(resp)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if trace != nil {
+		s.timings.add(timing)
+	}
+	if err != nil {
+		s.recordAttempt(AttemptInfo{Attempt: attempt, Ranged: true, Err: err, Hops: chain, Timing: timing})
+		s.cfg.debugDump.dump(attempt, req, nil, err)
+		return
+	}
+	s.recordAttempt(AttemptInfo{Attempt: attempt, Ranged: true, StatusCode: resp.StatusCode, Hops: chain, Timing: timing})
+	switch resp.StatusCode {
+	case http.StatusOK:
+		s.ifRangeChecked = true
+	case http.StatusPartialContent:
+		s.ifRangeChecked = true
+		s.ifRangeUnreliable = true
+	}
+}
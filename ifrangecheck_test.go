@@ -0,0 +1,65 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIfRangeCheck(t *testing.T) {
+	ctx := context.Background()
+
+	for _, honored := range []bool{true, false} {
+		var version atomic.Int32
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !honored {
+				r.Header.Del("If-Range")
+			}
+			content := "Hello World!"
+			if version.Load() > 0 {
+				content = "Howdy World!"
+			}
+			w.Header().Set("ETag", `"v1"`)
+			http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte(content)))
+		}))
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithFingerprint(4, false), WithIfRangeCheck())
+
+		if _, err := io.ReadFull(rsc, make([]byte, 4)); err != nil {
+			t.Fatal(err)
+		}
+		version.Store(1)
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(rsc)
+		if honored {
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "World!" {
+				t.Fatalf("got %q, want %q", got, "World!")
+			}
+		} else if !errors.Is(err, ErrContentChanged) {
+			t.Fatalf("got %v from a server ignoring If-Range, want %v", err, ErrContentChanged)
+		}
+		if rsc.IfRangeUnreliable() == honored {
+			t.Fatalf("got IfRangeUnreliable %v for a server honoring If-Range: %v", !honored, honored)
+		}
+		if checks := rsc.Stats().IfRangeChecks; checks != 1 {
+			t.Fatalf("got %d If-Range checks, want 1", checks)
+		}
+		rsc.Close()
+		s.Close()
+	}
+}
@@ -0,0 +1,78 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInterrupted is returned by a Read that was aborted by a concurrent Seek before it delivered any bytes.
+// The Read can be retried; it continues at the offset set by the Seek.
+var ErrInterrupted = errors.New("read interrupted by seek")
+
+// streamContext returns the context under which the stream is opened,
+// which is canceled when a Seek interrupts a Read.
+func (s *Seeker) streamContext() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streamContextLocked()
+}
+
+func (s *Seeker) streamContextLocked() context.Context {
+	if s.streamCtx == nil {
+		s.streamCtx, s.streamCancel = context.WithCancel(s.ctx)
+	}
+	return s.streamCtx
+}
+
+// cancelStream cancels the stream context; the next stream is opened under a new one.
+func (s *Seeker) cancelStream() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancelStreamLocked()
+}
+
+func (s *Seeker) cancelStreamLocked() {
+	if s.streamCancel != nil {
+		s.streamCancel()
+		s.streamCtx, s.streamCancel = nil, nil
+	}
+}
+
+// beginRead marks a Read as in flight and returns the stream context,
+// or false if a Seek is waiting for the stream.
+func (s *Seeker) beginRead() (context.Context, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seeking > 0 {
+		return nil, false
+	}
+	s.reading = true
+	return s.streamContextLocked(), true
+}
+
+// endRead marks the Read as done and reports whether a Seek interrupted it.
+func (s *Seeker) endRead() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	interrupted := s.interrupted
+	s.reading, s.interrupted = false, false
+	return interrupted
+}
+
+// interrupt announces a Seek and aborts a Read in flight by canceling the stream context.
+// The Seek must call endSeek once it holds the stream.
+func (s *Seeker) interrupt() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seeking++
+	if s.reading && !s.interrupted {
+		s.interrupted = true
+		s.cancelStreamLocked()
+	}
+}
+
+func (s *Seeker) endSeek() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seeking--
+}
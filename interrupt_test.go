@@ -0,0 +1,86 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSeekInterruptsRead(t *testing.T) {
+	ctx := context.Background()
+
+	content := []byte("Hello World! Hello Seeker!")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+			return
+		}
+		// Drip the first bytes, then stall until the client gives up.
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		for _, b := range content[:6] {
+			w.Write([]byte{b})
+			w.(http.Flusher).Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+		<-r.Context().Done()
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	head := make([]byte, 6)
+	if _, err := io.ReadFull(rsc, head); err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := rsc.Read(make([]byte, 10))
+		done <- result{n, err}
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := rsc.Seek(13, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	var got result
+	select {
+	case got = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("blocked Read was not interrupted")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("interrupt took %v", elapsed)
+	}
+	if got.n != 0 || !errors.Is(got.err, ErrInterrupted) {
+		t.Fatalf("got %d, %v, want 0, %v", got.n, got.err, ErrInterrupted)
+	}
+
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := content[13:]; !bytes.Equal(rest, want) {
+		t.Fatalf("got %q, want %q", rest, want)
+	}
+	if off := rsc.Offset(); off != int64(len(content)) {
+		t.Fatalf("got offset %d, want %d", off, len(content))
+	}
+}
@@ -0,0 +1,70 @@
+package httpseek
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// logURL returns u as written to the logs: without its password and, unless WithLogQuery, without its query,
+// since presigned URLs carry their credentials there.
+func (c *config) logURL(u *url.URL) string {
+	if !c.logQuery && (u.RawQuery != "" || u.ForceQuery) {
+		stripped := *u
+		stripped.RawQuery = ""
+		stripped.ForceQuery = false
+		u = &stripped
+	}
+	return u.Redacted()
+}
+
+// logRequest logs a request sent by roundTrip, which got resp or failed with err.
+func (c *config) logRequest(req, final *http.Request, resp *http.Response, err error, dur time.Duration) {
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", c.logURL(final.URL)),
+		slog.String("range", req.Header.Get("Range")),
+		slog.Duration("duration", dur),
+	}
+	if resp != nil {
+		attrs = append(attrs,
+			slog.Int("status", resp.StatusCode),
+			slog.String("content_range", resp.Header.Get(contentRangeKey)),
+		)
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", unwrapTransportError(err)))
+	}
+	c.logger.LogAttrs(req.Context(), slog.LevelDebug, "request", attrs...)
+}
+
+// logRedirect logs a redirect from req to location.
+func (c *config) logRedirect(req *http.Request, resp *http.Response, location *url.URL) {
+	c.logger.LogAttrs(req.Context(), slog.LevelDebug, "redirect",
+		slog.String("url", c.logURL(req.URL)),
+		slog.Int("status", resp.StatusCode),
+		slog.String("location", c.logURL(location)),
+	)
+}
+
+// logRetry logs the decision to retry a request as described by info.
+func (c *config) logRetry(ctx context.Context, info RetryInfo) {
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "retry",
+		slog.Int("retry", info.Retry),
+		slog.Int64("offset", info.Offset),
+		slog.String("reason", info.Reason.String()),
+		slog.Duration("delay", info.Delay),
+		slog.Any("error", info.Err),
+	)
+}
+
+// logGiveUp logs the decision to stop retrying a request for offset after retries retries.
+func (c *config) logGiveUp(ctx context.Context, offset int64, retries int, err error) {
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "giving up",
+		slog.Int("retries", retries),
+		slog.Int64("offset", offset),
+		slog.Any("error", err),
+	)
+}
@@ -0,0 +1,62 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestLogger(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+
+	for _, logQuery := range []bool{false, true} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test?X-Amz-Signature=secret", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transport := testutil.NewReplayTransport(content,
+			testutil.Respond(http.StatusFound, "Location", "/final?X-Amz-Signature=other"),
+			testutil.Respond(http.StatusTooManyRequests),
+		)
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		rsc := NewSeekerWithOptions(ctx, transport, req, WithRetry(3, time.Millisecond), WithLogger(logger), WithLogQuery(logQuery))
+
+		data, err := io.ReadAll(rsc)
+		rsc.Close()
+		if err != nil || string(data) != string(content) {
+			t.Fatalf("got %q, %v, want %q", data, err, content)
+		}
+
+		logs := buf.String()
+		if logQuery {
+			if !strings.Contains(logs, `url="http://example.com/test?X-Amz-Signature=secret"`) {
+				t.Errorf("logs miss the query:\n%s", logs)
+			}
+			continue
+		}
+		for _, want := range []string{
+			"msg=redirect url=http://example.com/test",
+			"status=302 location=http://example.com/final",
+			"msg=request method=GET url=http://example.com/final",
+			"status=429",
+			"msg=retry retry=1 offset=0 reason=\"too many requests\"",
+			"status=200",
+		} {
+			if !strings.Contains(logs, want) {
+				t.Errorf("logs miss %q:\n%s", want, logs)
+			}
+		}
+		if strings.Contains(logs, "Signature") {
+			t.Errorf("got the query in the logs:\n%s", logs)
+		}
+	}
+}
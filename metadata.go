@@ -0,0 +1,188 @@
+package httpseek
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Metadata describes a resource as reported by the server, without its content.
+type Metadata struct {
+	// URL is the URL that served the response, after redirects.
+	URL *url.URL
+	// Size is the total size of the content, or -1 if the server did not report it.
+	Size int64
+	// ETag is the value of the ETag header, if any.
+	ETag string
+	// LastModified is the value of the Last-Modified header, if any.
+	LastModified string
+	// ContentType is the value of the Content-Type header, if any.
+	ContentType string
+	// AcceptRanges lists the range units advertised in the Accept-Ranges header, or nil if absent.
+	AcceptRanges []string
+}
+
+// Validator returns the version of the content described by m.
+func (m Metadata) Validator() Validator {
+	return Validator{
+		ETag:         m.ETag,
+		LastModified: m.LastModified,
+		Size:         m.Size,
+	}
+}
+
+// MetadataClient looks up the metadata of many resources with one configuration.
+// It remembers the hosts that reject HEAD and probes them with a one-byte range
+// request directly. A MetadataClient is safe for concurrent use.
+type MetadataClient struct {
+	transport http.RoundTripper
+	cfg       config
+
+	mu     sync.Mutex
+	noHead map[string]bool
+}
+
+// NewMetadataClient returns a MetadataClient that sends requests with transport,
+// or http.DefaultTransport if nil, configured by opts like a Seeker.
+func NewMetadataClient(transport http.RoundTripper, opts ...Option) *MetadataClient {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &MetadataClient{
+		transport: transport,
+		cfg:       newConfig(opts),
+		noHead:    map[string]bool{},
+	}
+}
+
+// FetchMetadata looks up the metadata of the resource addressed by req without
+// reading its content. See MetadataClient.Fetch.
+func FetchMetadata(ctx context.Context, transport http.RoundTripper, req *http.Request, opts ...Option) (Metadata, error) {
+	return NewMetadataClient(transport, opts...).Fetch(ctx, req)
+}
+
+// Fetch looks up the metadata of the resource addressed by req without reading its content.
+// It asks with a HEAD request first and falls back to a one-byte range request, with retries,
+// when the server rejects HEAD, fails it, or omits Content-Length.
+func (c *MetadataClient) Fetch(ctx context.Context, req *http.Request) (Metadata, error) {
+	l := &metadataLookup{cfg: &c.cfg, transport: c.transport, req: req}
+
+	host := req.URL.Host
+	c.mu.Lock()
+	noHead := c.noHead[host]
+	c.mu.Unlock()
+	if !noHead {
+		resp, final, err := l.head(ctx)
+		if err == nil {
+			_ = resp.Body.Close()
+			switch {
+			case resp.StatusCode == http.StatusOK && resp.ContentLength >= 0:
+				return l.metadata(final, resp, resp.ContentLength)
+			case resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented:
+				c.mu.Lock()
+				c.noHead[host] = true
+				c.mu.Unlock()
+			}
+		}
+	}
+
+	var m Metadata
+	err := retryLoop(ctx, l.cfg, 0, time.Now(), true, func() error {
+		var err error
+		m, err = l.probe(ctx)
+		return err
+	}, nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return m, nil
+}
+
+// metadataLookup is the state of one lookup of MetadataClient.Fetch, sent with the configuration
+// of the client without the stream, counters and learned state of a Seeker.
+type metadataLookup struct {
+	cfg       *config
+	transport http.RoundTripper
+	req       *http.Request
+}
+
+// send sends one hop of a request of the lookup.
+func (l *metadataLookup) send(req *http.Request, hop int) (*http.Response, error) {
+	return l.transport.RoundTrip(l.cfg.traceHop(req, hop))
+}
+
+// head sends a HEAD request for the resource.
+func (l *metadataLookup) head(ctx context.Context) (*http.Response, *http.Request, error) {
+	req := l.req.Clone(ctx)
+	req.Method = http.MethodHead
+	req.Body = nil
+	req.GetBody = nil
+	req.ContentLength = 0
+	resp, req, _, err := roundTrip(l.cfg, req, l.send)
+	return resp, req, err
+}
+
+// probe requests the first byte of the resource once, returning an error that retryLoop classifies on failure.
+func (l *metadataLookup) probe(ctx context.Context) (Metadata, error) {
+	ctx, timer := l.cfg.startAttempt(ctx)
+	req := l.req.Clone(ctx)
+	req.Header.Set("Range", "bytes=0-0")
+	resp, req, _, err := roundTrip(l.cfg, req, l.send)
+	resp, err = timer.stop(0, resp, err)
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	size := int64(-1)
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		if contentEncoding(resp.Header) == "" {
+			size = resp.ContentLength
+		}
+	case http.StatusPartialContent:
+		if encoding := contentEncoding(resp.Header); encoding != "" {
+			err = &EncodedRangeError{Encoding: encoding}
+			break
+		}
+		contentRange := resp.Header.Get(contentRangeKey)
+		if contentRange == "" {
+			err = ErrNoContentRange
+			break
+		}
+		_, _, size, err = getContentLength(contentRange, 0, 1, l.cfg.strictContentRange)
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header, serverError: l.cfg.serverErrorRetries > 0}
+	default:
+		err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	if classifyRetry(err) == RetryServerError {
+		// Drain the error page so the connection can be reused by the retry.
+		drainBody(resp)
+	} else {
+		_ = resp.Body.Close()
+	}
+	if err != nil {
+		return Metadata{}, err
+	}
+	return l.metadata(req, resp, size)
+}
+
+// metadata returns the metadata reported by resp, the response to req, for content of the given size.
+func (l *metadataLookup) metadata(req *http.Request, resp *http.Response, size int64) (Metadata, error) {
+	m := Metadata{
+		URL:          req.URL,
+		Size:         size,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		AcceptRanges: parseAcceptRanges(resp.Header),
+	}
+	if l.cfg.validatorPolicy == ValidatorRequired && ifRangeValidator(l.cfg.validatorPolicy, m.ETag, m.LastModified) == "" {
+		return Metadata{}, ErrNoValidator
+	}
+	return m, nil
+}
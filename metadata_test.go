@@ -0,0 +1,203 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFetchMetadata(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var heads, gets atomic.Int64
+	handler := func(rejectHead bool) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				heads.Add(1)
+				if rejectHead {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+			} else {
+				gets.Add(1)
+			}
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Type", "text/plain")
+			http.ServeContent(w, r, "test", modified, bytes.NewReader(content))
+		})
+	}
+
+	tests := []struct {
+		name       string
+		rejectHead bool
+		wantHeads  int64
+		wantGets   int64
+	}{
+		{"head", false, 2, 0},
+		{"head rejected", true, 1, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			heads.Store(0)
+			gets.Store(0)
+			s := httptest.NewServer(handler(tt.rejectHead))
+			defer s.Close()
+
+			client := NewMetadataClient(s.Client().Transport)
+			for i := 0; i < 2; i++ {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/file", nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				m, err := client.Fetch(ctx, req)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := Metadata{
+					URL:          req.URL,
+					Size:         int64(len(content)),
+					ETag:         `"v1"`,
+					LastModified: modified.Format(http.TimeFormat),
+					ContentType:  "text/plain",
+					AcceptRanges: []string{"bytes"},
+				}
+				if m.URL.String() != want.URL.String() {
+					t.Errorf("URL = %v, want %v", m.URL, want.URL)
+				}
+				m.URL = want.URL
+				if !reflect.DeepEqual(m, want) {
+					t.Errorf("Fetch() = %+v, want %+v", m, want)
+				}
+			}
+			if got := heads.Load(); got != tt.wantHeads {
+				t.Errorf("HEAD requests = %d, want %d", got, tt.wantHeads)
+			}
+			if got := gets.Load(); got != tt.wantGets {
+				t.Errorf("GET requests = %d, want %d", got, tt.wantGets)
+			}
+		})
+	}
+}
+
+func TestFetchMetadataRedirect(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/final" {
+			http.Redirect(w, r, "/final", http.StatusFound)
+			return
+		}
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/start", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := FetchMetadata(ctx, s.Client().Transport, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Size != 12 {
+		t.Errorf("Size = %d, want 12", m.Size)
+	}
+	if m.URL == nil || m.URL.Path != "/final" {
+		t.Errorf("URL = %v, want path /final", m.URL)
+	}
+}
+
+func TestFetchMetadataRetry(t *testing.T) {
+	ctx := context.Background()
+	var gets atomic.Int64
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if gets.Add(1) == 1 {
+			w.WriteHeader(http.StatusRequestTimeout)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := FetchMetadata(ctx, s.Client().Transport, req, WithRetry(3, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Size != 12 {
+		t.Errorf("Size = %d, want 12", m.Size)
+	}
+	if got := gets.Load(); got != 2 {
+		t.Errorf("GET requests = %d, want 2", got)
+	}
+}
+
+func TestFetchMetadataNotFound(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.NotFoundHandler())
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = FetchMetadata(ctx, s.Client().Transport, req)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("FetchMetadata() error = %v, want 404 StatusError", err)
+	}
+}
+
+func TestFetchMetadataConcurrent(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	client := NewMetadataClient(s.Client().Transport)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			m, err := client.Fetch(ctx, req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if m.Size != 12 {
+				t.Errorf("Size = %d, want 12", m.Size)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,685 @@
+package httpseek
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"time"
+)
+
+// Option configures a Seeker.
+type Option func(*config)
+
+type config struct {
+	debugDump        *debugDumper
+	auditWriter      io.Writer
+	contentTypeCheck ContentTypeCheck
+	followRedirects  bool
+	maxRedirects     int
+	trustedHosts     []string
+	redirectPolicy   func(prev *http.Request, next *url.URL) error
+	expectedSize     int64
+
+	hedgeDelay    time.Duration
+	hedgeMaxExtra int
+
+	retries        int
+	retriesSet     bool
+	retryBackoff   time.Duration
+	retryHandler   func(RetryInfo) error
+	maxElapsed     time.Duration
+	requestTimeout time.Duration
+	fastFail       bool
+	fastRetries    int
+	fastBackoff    time.Duration
+
+	serverErrorRetries int
+	serverErrorBackoff time.Duration
+	maxRetryAfter      time.Duration
+
+	preflight            bool
+	identityEncoding     bool
+	encodedRangeFallback bool
+	verifyDigest         bool
+
+	progress func(readBytes, totalBytes int64)
+	recorder Recorder
+	logger   *slog.Logger
+	logQuery bool
+
+	restartAfter int
+	restartMode  RestartMode
+
+	minThroughput    int64
+	throughputWindow time.Duration
+
+	sizeChangePolicy  SizeChangePolicy
+	statusActions     map[int]StatusAction
+	listeners         []func(Event)
+	skipThreshold     int64
+	overlapVerify     int
+	tailPrefetch      int64
+	readAhead         int64
+	memoryBudget      *MemoryBudget
+	fingerprintLen    int
+	fingerprintRehash bool
+	warmup            bool
+	cacheBypass       http.Header
+	signer            Signer
+	preparer          func(ctx context.Context, req *http.Request) error
+	validatorPolicy   ValidatorPolicy
+	followPoll        time.Duration
+	followIdle        time.Duration
+	rangeFallback     int64
+	ifRangeCheck      bool
+	blockSize         int64
+	cacheMax          int64
+	cacheStore        Cache
+	bytesPerSecond    int64
+	clientTrace       func(TraceInfo) *httptrace.ClientTrace
+	maxBytesPerRead   int
+
+	strictContentRange bool
+
+	// sectionEnd bounds the ranges requested for the stream, as set by NewSectionSeeker, or is 0 for no bound.
+	sectionEnd int64
+}
+
+// NewSeekerWithOptions handles reading from an HTTP endpoint using a GET request, configured by opts.
+func NewSeekerWithOptions(ctx context.Context, transport http.RoundTripper, req *http.Request, opts ...Option) *Seeker {
+	s := &Seeker{
+		ctx:       ctx,
+		transport: transport,
+		req:       req,
+		size:      -1,
+		cfg:       newConfig(opts),
+	}
+	if s.cfg.expectedSize >= 0 {
+		s.size = s.cfg.expectedSize
+	}
+	s.init()
+	if s.cfg.preflight {
+		s.preflight()
+	}
+	if s.cfg.warmup {
+		s.startWarmup()
+	}
+	return s
+}
+
+// init sets up the state of s derived from its config, for NewSeekerWithOptions and Clone.
+func (s *Seeker) init() {
+	if s.cfg.auditWriter != nil {
+		s.audit = newAuditLog(s.cfg.auditWriter)
+	}
+	s.blocks.budget, s.blocks.maxBytes = s.cfg.memoryBudget, s.cfg.cacheMax
+	if s.cfg.bytesPerSecond > 0 {
+		s.limiter = newRateLimiter(s.cfg.bytesPerSecond)
+	}
+}
+
+// newConfig returns the defaults configured by opts.
+func newConfig(opts []Option) config {
+	c := config{
+		followRedirects:  true,
+		maxRedirects:     defaultMaxRedirects,
+		expectedSize:     -1,
+		retryBackoff:     defaultBackoff,
+		maxRetryAfter:    defaultMaxBackoff,
+		identityEncoding: true,
+		recorder:         nopRecorder{},
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.ifRangeCheck && c.fingerprintLen <= 0 {
+		c.fingerprintLen = defaultCheckFingerprint
+	}
+	if c.followPoll > 0 && c.sizeChangePolicy == SizeChangeFail {
+		c.sizeChangePolicy = SizeChangeAcceptGrowth
+	}
+	return c
+}
+
+// WithDebugDump writes a dump of every attempt that ends in an error or an unexpected status to w,
+// including an attempt whose body breaks before the end of the content.
+// The dump holds the request headers, with credentials and the values of the query redacted, and the response
+// headers followed by up to bodyBytes of the response body, which a broken body leaves out.
+func WithDebugDump(w io.Writer, bodyBytes int) Option {
+	return func(c *config) {
+		c.debugDump = &debugDumper{
+			w:         w,
+			bodyBytes: bodyBytes,
+		}
+	}
+}
+
+// WithContentTypeCheck sets how the Content-Type of resumed responses is compared with the first response.
+// The default is ContentTypeExact.
+func WithContentTypeCheck(check ContentTypeCheck) Option {
+	return func(c *config) {
+		c.contentTypeCheck = check
+	}
+}
+
+// WithRetry sets how many times a failed request is retried when the failure is classified as retryable,
+// such as a transport error, 408 Request Timeout, 425 Too Early or 429 Too Many Requests.
+// The first retry waits backoff, and the delay doubles after each further retry.
+// A retry of a response with a Retry-After header waits the requested delay instead, see WithMaxRetryAfter.
+// Retrying is disabled by default, and with a maxRetries of 0.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.retries = maxRetries
+		c.retriesSet = true
+		c.retryBackoff = backoff
+	}
+}
+
+// WithRetryHandler sets a handler called before each retry.
+// Returning an error aborts the request with that error.
+func WithRetryHandler(handler func(RetryInfo) error) Option {
+	return func(c *config) {
+		c.retryHandler = handler
+	}
+}
+
+// WithFastFail sets a separate retry budget used until the first byte of the content has been delivered,
+// so a request that never works fails quickly while an established transfer keeps the patient budget of WithRetry.
+func WithFastFail(maxRetries int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.fastFail = true
+		c.fastRetries = maxRetries
+		c.fastBackoff = backoff
+	}
+}
+
+// WithServerErrorRetry retries requests answered with 500, 502, 503 or 504, as sent by overloaded
+// or misconfigured proxies, up to maxRetries times. The first retry waits backoff, and the delay
+// doubles after each further retry. The budget is separate from WithRetry, and other statuses
+// such as 403 or 404 are not retried.
+// By default these statuses are returned to the caller like any other unknown status.
+func WithServerErrorRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *config) {
+		c.serverErrorRetries = maxRetries
+		c.serverErrorBackoff = backoff
+	}
+}
+
+// WithMaxRetryAfter caps the delay requested by the Retry-After header of a retried response,
+// such as 429 Too Many Requests or 503 Service Unavailable, at d.
+// The default is 30s.
+func WithMaxRetryAfter(d time.Duration) Option {
+	return func(c *config) {
+		c.maxRetryAfter = d
+	}
+}
+
+// WithMaxElapsed limits retrying to d measured from the first attempt of the transfer,
+// regardless of how many retries that allows; retrying stops with ErrRetriesExhausted once it has passed,
+// wrapping a TimeoutError of kind ErrBudgetExceeded.
+// A backoff that would end past the budget is shortened so the final attempt is made when it expires.
+// Without WithRetry, the budget is the only bound: failures are retried, with the default backoff,
+// until it expires. With WithRetry, retrying also stops once its count is used up.
+func WithMaxElapsed(d time.Duration) Option {
+	return func(c *config) {
+		c.maxElapsed = d
+	}
+}
+
+// WithRequestTimeout cancels a request for the content that got no response headers within d,
+// independently of the context of the Seeker, and fails it with a TimeoutError of kind ErrAttemptTimeout,
+// which is retried as RetryAttemptTimeout and, once retries are exhausted, returned by Read for
+// NewMustReader to resume. The body of a response received in time is read under the context of the Seeker.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.requestTimeout = d
+	}
+}
+
+// WithRestartAfter restarts the transfer as selected by mode
+// once resuming has failed n consecutive times at the same offset.
+func WithRestartAfter(n int, mode RestartMode) Option {
+	return func(c *config) {
+		c.restartAfter = n
+		c.restartMode = mode
+	}
+}
+
+// WithFollowRedirects sets whether redirects are followed, which is the default.
+// When disabled, a 3xx response is the result of the request:
+// Response returns it, Size is -1 and Read returns its body.
+func WithFollowRedirects(follow bool) Option {
+	return func(c *config) {
+		c.followRedirects = follow
+	}
+}
+
+// WithSizeChangePolicy sets what happens when a response reports a total size
+// different from the one learned earlier. The default is SizeChangeFail.
+func WithSizeChangePolicy(policy SizeChangePolicy) Option {
+	return func(c *config) {
+		c.sizeChangePolicy = policy
+	}
+}
+
+// WithOnSizeKnown sets a callback invoked when the total size is learned or an accepted size change updates it.
+// It is a listener for SizeDiscovered events, see WithEventListener.
+func WithOnSizeKnown(fn func(size int64)) Option {
+	return WithEventListener(func(ev Event) {
+		if ev, ok := ev.(SizeDiscovered); ok {
+			fn(ev.Size)
+		}
+	})
+}
+
+// WithPreflight sends a HEAD request from NewSeekerWithOptions, before any GET, so Size, Validator
+// and Response report the size, the validators and the headers of the content as soon as it returns.
+// If the server rejects HEAD, such as with 405 or 501, or the request fails, they are learned
+// from the first GET as without the option.
+func WithPreflight() Option {
+	return func(c *config) {
+		c.preflight = true
+	}
+}
+
+// WithIdentityEncoding sets whether every request, including redirects and resumed ranges,
+// is sent with Accept-Encoding: identity, so offsets always count bytes of the uncompressed content.
+// It is enabled by default; disabling it leaves the Accept-Encoding of the request, or of the transport, in place.
+func WithIdentityEncoding(enabled bool) Option {
+	return func(c *config) {
+		c.identityEncoding = enabled
+	}
+}
+
+// WithEncodedRangeFallback serves a resume whose range response carries a Content-Encoding,
+// which fails with ErrEncodedRange by default, from a request for the whole content instead:
+// the response is decoded if it is gzip or deflate encoded and the bytes before the offset are discarded.
+// ReadAt and other bounded requests still fail with ErrEncodedRange.
+func WithEncodedRangeFallback() Option {
+	return func(c *config) {
+		c.encodedRangeFallback = true
+	}
+}
+
+// WithDigestVerification hashes the content as Read delivers it from the start and, at the end of the content,
+// fails Read with a *DigestError matching ErrDigestMismatch instead of returning io.EOF if the hash differs from the digest
+// announced by the first response: a sha-256 or sha-512 Repr-Digest, or else the Content-MD5 of a full response.
+// Nothing is verified if no digest was announced, or if Read did not deliver every byte from offset 0,
+// such as after a seek past bytes never read.
+func WithDigestVerification() Option {
+	return func(c *config) {
+		c.verifyDigest = true
+	}
+}
+
+// WithProgress calls fn from Read, and so from WriteTo and the bodies of NewMustReaderTransportWithOptions,
+// with the number of bytes delivered so far and the total size, or -1 if unknown.
+// To keep it cheap, fn is called once at least 64 KiB were delivered since the previous call,
+// when the last byte of a known size is delivered, and at the end of the content.
+// fn is called synchronously and must not call Read, Seek or Close.
+func WithProgress(fn func(readBytes, totalBytes int64)) Option {
+	return func(c *config) {
+		c.progress = fn
+	}
+}
+
+// WithRecorder sends the requests, bytes and retries of the Seeker to r.
+// With NewMustReaderTransportWithOptions, r receives those of every response read through the transport.
+func WithRecorder(r Recorder) Option {
+	return func(c *config) {
+		if r == nil {
+			r = nopRecorder{}
+		}
+		c.recorder = r
+	}
+}
+
+// WithClientTrace calls newTrace before every request of the Seeker is sent, and before each redirect
+// it follows, and attaches the ClientTrace it returns, if not nil, to that request alone, so that the DNS,
+// connect, TLS and first byte timings of every hop can be told apart. The trace is attached in addition to
+// the one measuring AttemptTiming and to any trace of the context of the request.
+func WithClientTrace(newTrace func(info TraceInfo) *httptrace.ClientTrace) Option {
+	return func(c *config) {
+		c.clientTrace = newTrace
+	}
+}
+
+// WithLogger logs every request at the Debug level of logger, with its URL, Range, status, Content-Range
+// and duration, as well as every redirect followed and every decision to retry a request or to give up.
+// With NewMustReaderTransportWithOptions, the requests of every response read through the transport are logged.
+// URLs are logged without their query, which carries the credentials of presigned URLs; see WithLogQuery.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
+
+// WithLogQuery sets whether the URLs logged by WithLogger keep their query. It is disabled by default.
+func WithLogQuery(enabled bool) Option {
+	return func(c *config) {
+		c.logQuery = enabled
+	}
+}
+
+// WithEventListener adds fn to the listeners of the events of the Seeker.
+// Events are delivered synchronously, in the order they happened, before the call that caused them returns,
+// such as the Read that learned the size; deliveries never overlap, even when ReadAt is called concurrently.
+// A listener may call methods that report state, such as Size and Stats, but not Read, Seek or Close.
+func WithEventListener(fn func(Event)) Option {
+	return func(c *config) {
+		c.listeners = append(c.listeners, fn)
+	}
+}
+
+// WithMaxRedirects sets how many redirects are followed for one request; the default is 10.
+// A request still redirected after that many fails with a TooManyRedirectsError.
+// With 0, redirects are not followed, as with WithFollowRedirects(false).
+func WithMaxRedirects(n int) Option {
+	return func(c *config) {
+		c.maxRedirects = n
+	}
+}
+
+// WithTrustedHosts sets hosts that keep the credentials of the request when a redirect leads to them.
+// By default, Authorization, Cookie and similar headers are dropped when a redirect leads away
+// from the host of the request and its subdomains, as net/http does. A trusted host also covers its subdomains.
+func WithTrustedHosts(hosts ...string) Option {
+	return func(c *config) {
+		c.trustedHosts = append(c.trustedHosts, hosts...)
+	}
+}
+
+// WithRedirectPolicy sets a function called before each redirect is followed, for every request the Seeker makes.
+// It may change next to rewrite the target; returning an error stops the request with a RedirectError wrapping it.
+func WithRedirectPolicy(policy func(prev *http.Request, next *url.URL) error) Option {
+	return func(c *config) {
+		c.redirectPolicy = policy
+	}
+}
+
+// WithExpectedSize sets the size of the content when it is known in advance, such as from a manifest.
+// Seek with io.SeekEnd then works without a request, and a response reporting another size
+// fails with a SizeChangedError, subject to the size-change policy.
+func WithExpectedSize(size int64) Option {
+	return func(c *config) {
+		c.expectedSize = size
+	}
+}
+
+// WithHedging sends another copy of a request each time delay passes without a response,
+// up to maxExtra extra copies, and uses whichever responds first.
+// Hedging only applies to establishing a request, not to reading its body.
+// A request whose copies all fail is not hedged further; the failure is retried as configured by WithRetry.
+func WithHedging(delay time.Duration, maxExtra int) Option {
+	return func(c *config) {
+		c.hedgeDelay = delay
+		c.hedgeMaxExtra = maxExtra
+	}
+}
+
+// WithMinThroughput aborts the current attempt and resumes with a new request
+// when the server delivers fewer than bytesPerSec bytes per second, averaged over window.
+// Only the time spent waiting for the server counts towards the window, not the time between Read calls,
+// and the first window of a stream is a grace period.
+func WithMinThroughput(bytesPerSec int64, window time.Duration) Option {
+	return func(c *config) {
+		c.minThroughput = bytesPerSec
+		c.throughputWindow = window
+	}
+}
+
+// WithAuditLog writes one JSON line to w for every response segment read,
+// with the URL, validators, requested and delivered ranges, byte count, timestamps, attempt and outcome,
+// and a terminal summary line when the Seeker is closed.
+// Lines are written from a background goroutine; if w cannot keep up they are dropped and counted in Stats.
+// The Seeker must be closed to flush the log.
+func WithAuditLog(w io.Writer) Option {
+	return func(c *config) {
+		c.auditWriter = w
+	}
+}
+
+// WithStatusAction sets how responses with the given status code are handled.
+// The table is consulted before the built-in handling; codes without an entry keep the default behavior.
+func WithStatusAction(code int, action StatusAction) Option {
+	return func(c *config) {
+		if c.statusActions == nil {
+			c.statusActions = map[int]StatusAction{}
+		}
+		c.statusActions[code] = action
+	}
+}
+
+// WithOnComplete calls fn once, from the Read that delivers the last byte of the content.
+// See IsComplete for when a transfer counts as complete; fn is never called for a transfer closed early.
+// It is a listener for TransferComplete events, see WithEventListener.
+func WithOnComplete(fn func(Summary)) Option {
+	return WithEventListener(func(ev Event) {
+		if ev, ok := ev.(TransferComplete); ok {
+			fn(ev.Summary)
+		}
+	})
+}
+
+// WithSkipThreshold satisfies forward seeks of at most n bytes on the open response body,
+// by reading and discarding the bytes in between instead of making a new request.
+// Discarded bytes are counted in WastedBytes. Backward seeks and longer jumps are unaffected.
+func WithSkipThreshold(n int64) Option {
+	return func(c *config) {
+		c.skipThreshold = n
+	}
+}
+
+// WithOverlapVerify makes every resume request n bytes before the resumed offset
+// and compare them with the last bytes already delivered by Read, which are kept for this.
+// A mismatch fails with an OverlapMismatchError before any byte of the resumed response is delivered.
+// The overlap is shorter when fewer delivered bytes directly precede the offset, and 0 disables the check.
+// The re-requested bytes are counted in OverlapBytes.
+func WithOverlapVerify(n int) Option {
+	return func(c *config) {
+		c.overlapVerify = n
+	}
+}
+
+// WithTailPrefetch requests the last n bytes of the content with a suffix range alongside the first request,
+// for formats such as ZIP, MP4 or Parquet that keep their metadata at the end.
+// Reads within the prefetched tail are then served locally instead of opening a new request.
+// A content shorter than n is prefetched whole. Close cancels a prefetch still in flight.
+func WithTailPrefetch(n int64) Option {
+	return func(c *config) {
+		c.tailPrefetch = n
+	}
+}
+
+// WithReadAhead keeps a goroutine reading the open body into a buffer of up to n bytes ahead of Read,
+// so that sequential reads are usually copied from memory while the next bytes are in flight.
+// The buffer takes its memory from the budget of WithMemoryBudget, and no read-ahead is done when none is left.
+// Prefetching stops when a Seek, Release or Close closes the body; an error of the body is returned
+// by Read once the bytes buffered before it are delivered, and resumed from as without read-ahead.
+func WithReadAhead(n int64) Option {
+	return func(c *config) {
+		c.readAhead = n
+	}
+}
+
+// WithBytesPerSecond limits the bytes delivered by Read and WriteTo to n per second
+// on average, with bursts of a tenth of a second. The limit applies to the Seeker as a whole, across
+// reconnects, retries and resumed range requests, and each Clone has a limit of its own. Waiting for the limit
+// ends with the context of the Seeker, or with a Seek made concurrently, as reading from the network does.
+func WithBytesPerSecond(n int64) Option {
+	return func(c *config) {
+		c.bytesPerSecond = n
+	}
+}
+
+// WithMemoryBudget makes the buffers of the Seeker, such as the tail prefetch and the overlap verification,
+// take their memory from b, which may be shared by many Seekers. Memory is given back on Close.
+func WithMemoryBudget(b *MemoryBudget) Option {
+	return func(c *config) {
+		c.memoryBudget = b
+	}
+}
+
+// WithBlockAlignment widens the range requests of ReadAt to blocks of blockSize bytes aligned to multiples of it,
+// so that caches in front of the origin see the same ranges from every client. The requested bytes are cut from
+// the blocks, and the 8 most recently used blocks are kept, with memory taken from the budget of WithMemoryBudget,
+// to serve neighboring reads without a request. The missing blocks of one ReadAt are fetched with a single request.
+// Fetched bytes that are neither delivered nor kept count as WastedBytes. Read and Seek are not affected.
+func WithBlockAlignment(blockSize int64) Option {
+	return func(c *config) {
+		c.blockSize = blockSize
+	}
+}
+
+// WithCache keeps the most recently used chunks of chunkSize bytes, aligned to multiples of it, up to maxBytes
+// in total, to serve Reads after a Seek and ReadAt without a request. Chunks are collected from the bytes
+// delivered by Read and from the requests of ReadAt, which are widened to whole chunks as with WithBlockAlignment,
+// and take their memory from the budget of WithMemoryBudget. Every chunk is dropped when a response reports
+// another ETag or size than those the chunks were cut from. Bytes served from the chunks are reported
+// to the Recorder of WithRecorder if it is a CacheRecorder, and counted as BlockCacheHits.
+// A chunkSize of 0 or less uses chunks of 4 MiB. WithCache replaces WithBlockAlignment.
+// See WithChunkCache to keep the chunks elsewhere, such as on disk.
+func WithCache(maxBytes int64, chunkSize int) Option {
+	return func(c *config) {
+		if chunkSize <= 0 {
+			chunkSize = defaultChunkSize
+		}
+		c.cacheMax = maxBytes
+		c.blockSize = int64(chunkSize)
+		c.cacheStore = nil
+	}
+}
+
+// WithChunkCache is WithCache with the chunks stored in c, which bounds their size itself,
+// such as a DiskCache kept across runs. Chunks are stored and looked up under the URL of the request,
+// the ETag and size of the content and chunkSize, so they are only used once a response reported an ETag,
+// and chunks of another version of the content are never served. As with WithCache, a chunkSize of 0 or less
+// uses chunks of 4 MiB. WithChunkCache replaces WithCache.
+func WithChunkCache(c Cache, chunkSize int) Option {
+	return func(cfg *config) {
+		if chunkSize <= 0 {
+			chunkSize = defaultChunkSize
+		}
+		cfg.cacheStore = c
+		cfg.cacheMax = 0
+		cfg.blockSize = int64(chunkSize)
+	}
+}
+
+// WithStrictContentRange only accepts the Content-Range of range responses in the exact form "bytes first-last/size",
+// failing fast on servers that do not conform. By default, the unit is matched case-insensitively
+// and whitespace around the values is ignored.
+func WithStrictContentRange() Option {
+	return func(c *config) {
+		c.strictContentRange = true
+	}
+}
+
+// WithMaxBytesPerRead caps the bytes a single Read delivers, and so pulls from the network, at n,
+// whatever the size of the buffer; larger buffers get short reads, which io.Reader permits and io.ReadFull handles.
+// Callers regain a cancellation point every n bytes, at the cost of more calls, and so less throughput,
+// for small n.
+func WithMaxBytesPerRead(n int) Option {
+	return func(c *config) {
+		c.maxBytesPerRead = n
+	}
+}
+
+// WithWarmup sends the first request in the background as soon as the Seeker is created,
+// so the first Read finds the body already open. Its errors are returned by the first Read.
+// A Seek away from the start before reading closes the warm-up body.
+func WithWarmup() Option {
+	return func(c *config) {
+		c.warmup = true
+	}
+}
+
+// WithCacheBypass makes resumes escalate when one reveals changed or corrupt content, such as an overlap
+// or content-type mismatch, to work around intermediaries that serve stale cached content: the resume is
+// tried again with the validators of the first response (ResumeValidated), then also with the header
+// directives (ResumeCacheBypass), which default to Cache-Control: no-cache and Pragma: no-cache when header is nil.
+// Requests before the first such signal are sent unchanged; each attempt records its level.
+func WithCacheBypass(header http.Header) Option {
+	return func(c *config) {
+		if header == nil {
+			header = defaultCacheBypass
+		}
+		c.cacheBypass = header
+	}
+}
+
+// WithSigner signs every request the Seeker sends, including retries, probes and each redirect hop,
+// after everything else has been set on it, such as the Range, If-Range and the redirect target,
+// so header-based signatures cover exactly what is sent. Hedged copies carry the same signature.
+func WithSigner(signer Signer) Option {
+	return func(c *config) {
+		c.signer = signer
+	}
+}
+
+// WithRequestPreparer calls prepare on every request the Seeker sends, including retries, probes
+// and each redirect hop, right before it is signed and sent, such as to set a fresh Authorization header
+// in place of an expiring one. An error fails the attempt with a *PrepareError, which is retried
+// as enabled by WithRetry with the reason RetryPrepare, and reported like any other failure otherwise.
+func WithRequestPreparer(prepare func(ctx context.Context, req *http.Request) error) Option {
+	return func(c *config) {
+		c.preparer = prepare
+	}
+}
+
+// WithValidatorPolicy sets which validator of the first response makes later range requests conditional
+// with If-Range. The default is ValidatorBestEffort.
+func WithValidatorPolicy(policy ValidatorPolicy) Option {
+	return func(c *config) {
+		c.validatorPolicy = policy
+	}
+}
+
+// WithFollow makes Read wait at the end of the content for data appended to it, like tail -f:
+// instead of returning io.EOF, it asks for the bytes after the end every pollInterval and delivers them as they appear.
+// Following ends with a TimeoutError of kind ErrFollowIdle once nothing was appended for idleTimeout, if positive,
+// or with the error of the context. A content that shrinks or is replaced at the same size, as when a log is rotated,
+// fails with a ContentChangedError so the caller can reopen it.
+// Growth is accepted as with SizeChangeAcceptGrowth, and each new size is reported to WithOnSizeKnown.
+func WithFollow(pollInterval, idleTimeout time.Duration) Option {
+	return func(c *config) {
+		c.followPoll = pollInterval
+		c.followIdle = idleTimeout
+	}
+}
+
+// WithRangeFallback accepts a 200 response with the full content to a request for bytes from an offset
+// of at most maxDiscard, as sent by servers that ignore Range, by reading and discarding the bytes before the offset.
+// Seeking then works, at the cost of transferring the skipped bytes, which count as WastedBytes.
+// Beyond maxDiscard, such responses still fail with ErrCodeForByteRange.
+func WithRangeFallback(maxDiscard int64) Option {
+	return func(c *config) {
+		c.rangeFallback = maxDiscard
+	}
+}
+
+// WithIfRangeCheck verifies, before the first resume, that the server honors If-Range, by asking for the first byte
+// with a validator known to be stale and expecting the full content in return. A server that answers with the range
+// instead is marked by IfRangeUnreliable, and every resume is then verified by re-hashing the fingerprinted prefix
+// as with WithFingerprint(n, true), even though the server sends a validator. The check costs one extra request.
+// Without WithFingerprint, the first 4 KiB are fingerprinted.
+func WithIfRangeCheck() Option {
+	return func(c *config) {
+		c.ifRangeCheck = true
+	}
+}
+
+// WithFingerprint hashes the first n bytes read from the start of the content into its Fingerprint,
+// for origins that send neither ETag nor Last-Modified. Size and Content-Type are compared on every resume anyway.
+// With rehash, each resume of such an origin first re-fetches the n bytes and fails with a ContentChangedError
+// if their hash differs; this costs a request per resume and is still a heuristic,
+// since a change after the first n bytes that keeps the size goes unnoticed.
+func WithFingerprint(n int, rehash bool) Option {
+	return func(c *config) {
+		c.fingerprintLen = n
+		c.fingerprintRehash = rehash
+	}
+}
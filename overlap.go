@@ -0,0 +1,98 @@
+package httpseek
+
+import (
+	"fmt"
+	"io"
+)
+
+// OverlapMismatchError is returned when the bytes re-requested before a resumed offset
+// differ from the bytes already delivered, meaning the content changed or was corrupted in transit.
+// It matches ErrContentChanged with errors.Is.
+type OverlapMismatchError struct {
+	// Offset is the offset of the first differing byte.
+	Offset int64
+}
+
+func (e *OverlapMismatchError) Error() string {
+	return fmt.Sprintf("resumed content differs from the delivered content at offset %d", e.Offset)
+}
+
+func (e *OverlapMismatchError) Is(target error) bool {
+	return target == ErrContentChanged
+}
+
+// keepDelivered remembers the last bytes delivered by Read, up to the configured overlap
+// or as much of it as the memory budget grants, so that a later resume can be checked against them.
+func (s *Seeker) keepDelivered(start int64, p []byte) {
+	if s.cfg.overlapVerify <= 0 || len(p) == 0 {
+		return
+	}
+	if s.tail == nil {
+		granted := s.cfg.memoryBudget.acquire(BufferRewind, int64(s.cfg.overlapVerify))
+		s.tail = make([]byte, 0, granted)
+	}
+	overlap := cap(s.tail)
+	if overlap == 0 {
+		return
+	}
+	if start != s.tailEnd {
+		s.tail = s.tail[:0]
+	}
+	if len(p) >= overlap {
+		s.tail = append(s.tail[:0], p[len(p)-overlap:]...)
+	} else {
+		if excess := len(s.tail) + len(p) - overlap; excess > 0 {
+			s.tail = s.tail[:copy(s.tail, s.tail[excess:])]
+		}
+		s.tail = append(s.tail, p...)
+	}
+	s.tailEnd = start + int64(len(p))
+}
+
+// releaseDelivered gives the memory of the remembered bytes back to the budget.
+func (s *Seeker) releaseDelivered() {
+	s.cfg.memoryBudget.release(int64(cap(s.tail)))
+	s.tail = nil
+	s.tailEnd = 0
+}
+
+// overlapBefore returns how many of the remembered bytes directly precede offset.
+func (s *Seeker) overlapBefore(offset int64) int {
+	tailStart := s.tailEnd - int64(len(s.tail))
+	if offset <= tailStart || offset > s.tailEnd {
+		return 0
+	}
+	return int(offset - tailStart)
+}
+
+// verifyOverlap reads the n bytes preceding offset from r and compares them with the remembered ones.
+func (s *Seeker) verifyOverlap(r io.Reader, offset int64, n int) error {
+	tailStart := s.tailEnd - int64(len(s.tail))
+	want := s.tail[offset-int64(n)-tailStart : offset-tailStart]
+	bufp := getBuffer(n)
+	defer putBuffer(bufp)
+	buf := *bufp
+
+	var read int
+	defer func() {
+		s.mu.Lock()
+		s.stats.OverlapBytes += int64(read)
+		s.mu.Unlock()
+	}()
+	for read < n {
+		got := buf[:min(len(buf), n-read)]
+		m, err := io.ReadFull(r, got)
+		for i := 0; i < m; i++ {
+			if got[i] != want[read+i] {
+				mismatch := read + i
+				read += m
+				return &OverlapMismatchError{Offset: offset - int64(n) + int64(mismatch)}
+			}
+		}
+		read += m
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOverlapVerify(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		overlap int
+		next    string
+		want    string
+		wantErr bool
+		wantOff int64
+		bytes   int64
+	}{
+		{name: "unchanged", overlap: 4, next: "Hello World!", want: "World!", bytes: 4},
+		{name: "offset shorter than overlap", overlap: 1024, next: "Hello World!", want: "World!", bytes: 6},
+		{name: "changed", overlap: 1024, next: "Jello World!", wantErr: true, wantOff: 0, bytes: 6},
+		{name: "changed outside overlap", overlap: 2, next: "Jello World!", want: "World!", bytes: 2},
+		{name: "disabled", overlap: 0, next: "Hello World!", want: "World!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := []byte("Hello World!")
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+			}))
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithOverlapVerify(tt.overlap))
+			defer rsc.Close()
+
+			if _, err := io.ReadFull(rsc, make([]byte, 6)); err != nil {
+				t.Fatal(err)
+			}
+			rsc.Release()
+			content = []byte(tt.next)
+
+			got, err := io.ReadAll(rsc)
+			if tt.wantErr {
+				var mismatchErr *OverlapMismatchError
+				if !errors.Is(err, ErrContentChanged) || !errors.As(err, &mismatchErr) || mismatchErr.Offset != tt.wantOff {
+					t.Fatalf("got %v, want mismatch at %d", err, tt.wantOff)
+				}
+				if len(got) != 0 {
+					t.Fatalf("got %q delivered after a mismatch", got)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != tt.want {
+					t.Fatalf("got %q, want %q", got, tt.want)
+				}
+			}
+			if stats := rsc.Stats(); stats.OverlapBytes != tt.bytes {
+				t.Fatalf("got %d overlap bytes, want %d", stats.OverlapBytes, tt.bytes)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package httpseek
+
+import (
+	"io"
+	"sync"
+)
+
+// bufferClasses are the sizes of the pooled scratch buffers.
+var bufferClasses = [...]int{4 << 10, 32 << 10, 256 << 10}
+
+var bufferPools [len(bufferClasses)]sync.Pool
+
+func init() {
+	for i, size := range bufferClasses {
+		size := size
+		bufferPools[i].New = func() any {
+			buf := make([]byte, size)
+			return &buf
+		}
+	}
+}
+
+// getBuffer returns a pooled scratch buffer of the smallest class holding n bytes,
+// or of the largest class if none does; callers loop over it for larger amounts.
+// The buffer must be given back with putBuffer and never retained or handed to the caller of an exported API.
+func getBuffer(n int) *[]byte {
+	for i, size := range bufferClasses {
+		if n <= size || i == len(bufferClasses)-1 {
+			return bufferPools[i].Get().(*[]byte)
+		}
+	}
+	panic("unreachable")
+}
+
+// putBuffer gives a buffer from getBuffer back to its pool.
+func putBuffer(buf *[]byte) {
+	for i, size := range bufferClasses {
+		if len(*buf) == size {
+			bufferPools[i].Put(buf)
+			return
+		}
+	}
+}
+
+// discard reads and drops n bytes from r through a pooled buffer.
+func discard(r io.Reader, n int64) error {
+	_, err := discardN(r, n)
+	return err
+}
+
+// discardN is discard that also returns how many bytes were dropped.
+func discardN(r io.Reader, n int64) (int64, error) {
+	bufp := getBuffer(int(min(n, int64(bufferClasses[len(bufferClasses)-1]))))
+	defer putBuffer(bufp)
+	buf := *bufp
+
+	var done int64
+	for done < n {
+		chunk := buf[:min(int64(len(buf)), n-done)]
+		m, err := io.ReadFull(r, chunk)
+		done += int64(m)
+		if err == io.EOF {
+			return done, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return done, err
+		}
+	}
+	return done, nil
+}
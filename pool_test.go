@@ -0,0 +1,68 @@
+package httpseek
+
+import (
+	"bytes"
+	"testing"
+)
+
+func newOverlapSeeker(content []byte, overlap int) *Seeker {
+	s := &Seeker{size: -1}
+	s.cfg.overlapVerify = overlap
+	s.keepDelivered(0, content)
+	return s
+}
+
+func TestScratchBuffersDoNotAllocate(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 100<<10)
+	r := bytes.NewReader(content)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		r.Reset(content)
+		if err := discard(r, int64(len(content))); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("discard: got %.1f allocations per run, want none", allocs)
+	}
+
+	s := newOverlapSeeker(content, 64<<10)
+	tail := content[len(content)-64<<10:]
+	allocs = testing.AllocsPerRun(100, func() {
+		r.Reset(tail)
+		if err := s.verifyOverlap(r, int64(len(content)), len(tail)); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("verifyOverlap: got %.1f allocations per run, want none", allocs)
+	}
+}
+
+func BenchmarkDiscard(b *testing.B) {
+	content := make([]byte, 1<<20)
+	r := bytes.NewReader(content)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(content)))
+	for i := 0; i < b.N; i++ {
+		r.Reset(content)
+		if err := discard(r, int64(len(content))); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyOverlap(b *testing.B) {
+	content := make([]byte, 1<<20)
+	s := newOverlapSeeker(content, 64<<10)
+	tail := content[len(content)-64<<10:]
+	r := bytes.NewReader(tail)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(tail)))
+	for i := 0; i < b.N; i++ {
+		r.Reset(tail)
+		if err := s.verifyOverlap(r, int64(len(content)), len(tail)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
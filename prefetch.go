@@ -0,0 +1,120 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	errNoBudget       = errors.New("no memory budget left for the tail prefetch")
+	errPrefetchClosed = errors.New("tail prefetch closed")
+)
+
+// tailPrefetch holds the last bytes of the content, fetched by a suffix range request
+// issued alongside the first request of the stream.
+type tailPrefetch struct {
+	done   chan struct{}
+	cancel context.CancelFunc
+
+	// n is the length requested, as granted by the memory budget.
+	n int64
+
+	// start, data and err are set before done is closed.
+	start int64
+	data  []byte
+	err   error
+
+	// used is guarded by the Seeker's mu.
+	used []Range
+}
+
+// startPrefetch issues the tail prefetch if it is configured and was not issued yet.
+func (s *Seeker) startPrefetch() {
+	if s.cfg.tailPrefetch <= 0 || s.prefetch != nil {
+		return
+	}
+	ctx, cancel := context.WithCancel(s.ctx)
+	pf := &tailPrefetch{
+		done:   make(chan struct{}),
+		cancel: cancel,
+		n:      s.cfg.memoryBudget.acquire(BufferReadAhead, s.cfg.tailPrefetch),
+	}
+	s.prefetch = pf
+	if pf.n == 0 {
+		pf.err = errNoBudget
+		close(pf.done)
+		return
+	}
+	go func() {
+		defer close(pf.done)
+		pf.start, pf.data, pf.err = s.fetchTail(ctx, pf.n)
+		s.cfg.memoryBudget.release(pf.n - int64(len(pf.data)))
+	}()
+}
+
+// fetchTail requests the last n bytes of the content with a suffix range.
+// A content shorter than n is fetched whole.
+func (s *Seeker) fetchTail(ctx context.Context, n int64) (int64, []byte, error) {
+	r, start, size, err := s.openTail(ctx, n)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer r.Close()
+
+	data := make([]byte, size-start)
+	read, err := io.ReadFull(r, data)
+	s.mu.Lock()
+	s.stats.PrefetchedBytes += int64(read)
+	s.mu.Unlock()
+	if err != nil {
+		return 0, nil, err
+	}
+	return start, data, nil
+}
+
+// prefetched returns the tail prefetch if it covers the current offset, waiting for it if it is still in flight.
+func (s *Seeker) prefetched() *tailPrefetch {
+	pf := s.prefetch
+	if pf == nil {
+		return nil
+	}
+	if size := s.Size(); size >= 0 && s.offset < size-pf.n {
+		return nil
+	}
+	select {
+	case <-pf.done:
+	case <-s.ctx.Done():
+		return nil
+	}
+	if pf.err != nil || s.offset < pf.start || s.offset > pf.start+int64(len(pf.data)) {
+		return nil
+	}
+	return pf
+}
+
+// readPrefetched serves a Read at the current offset from the tail prefetch.
+func (s *Seeker) readPrefetched(pf *tailPrefetch, p []byte) (int, error) {
+	if s.offset == pf.start+int64(len(pf.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, pf.data[s.offset-pf.start:])
+	s.mu.Lock()
+	pf.used = mergeRanges(append(pf.used, Range{Start: s.offset, End: s.offset + int64(n)}))
+	s.mu.Unlock()
+	return n, nil
+}
+
+// closePrefetch cancels the tail prefetch if it is still in flight
+// and gives its memory back to the budget.
+func (s *Seeker) closePrefetch() {
+	pf := s.prefetch
+	if pf == nil {
+		return
+	}
+	pf.cancel()
+	<-pf.done
+	s.cfg.memoryBudget.release(int64(len(pf.data)))
+	pf.data = nil
+	pf.err = errPrefetchClosed
+}
@@ -0,0 +1,116 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTailPrefetch(t *testing.T) {
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithTailPrefetch(100))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 4)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(-22, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content[len(content)-22:]) {
+		t.Fatalf("got %q, want %q", got, content[len(content)-22:])
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("got %d requests, want the stream and the prefetch", n)
+	}
+
+	stats := rsc.Stats()
+	if stats.PrefetchedBytes != 100 || stats.PrefetchUsedBytes != 22 {
+		t.Fatalf("got %d prefetched and %d used bytes, want 100 and 22", stats.PrefetchedBytes, stats.PrefetchUsedBytes)
+	}
+}
+
+func TestTailPrefetchSmallContent(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithTailPrefetch(1024))
+	defer rsc.Close()
+
+	if _, err := rsc.Seek(-6, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+	if stats := rsc.Stats(); stats.Requests != 1 || stats.PrefetchedBytes != 12 {
+		t.Fatalf("got %d requests and %d prefetched bytes, want 1 and 12", stats.Requests, stats.PrefetchedBytes)
+	}
+}
+
+func TestTailPrefetchCanceledByClose(t *testing.T) {
+	ctx := context.Background()
+
+	canceled := make(chan struct{})
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Range"), "bytes=-") {
+			<-r.Context().Done()
+			close(canceled)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithTailPrefetch(4))
+
+	if _, err := io.ReadFull(rsc, make([]byte, 5)); err != nil {
+		t.Fatal(err)
+	}
+	rsc.Close()
+
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("prefetch was not canceled by Close")
+	}
+}
@@ -0,0 +1,24 @@
+package httpseek
+
+// progressInterval is the number of bytes delivered between two calls of the WithProgress callback.
+const progressInterval = 64 << 10
+
+// reportProgress calls the WithProgress callback if enough bytes were delivered since the previous call,
+// the last byte of a known size was delivered, or Read reached the end of the content.
+func (s *Seeker) reportProgress(eof bool) {
+	if s.cfg.progress == nil {
+		return
+	}
+	s.mu.Lock()
+	read, total := s.stats.BytesRead, s.size
+	s.mu.Unlock()
+	if s.progressCalled && read == s.progressReported {
+		return
+	}
+	if !eof && read-s.progressReported < progressInterval && read != total {
+		return
+	}
+	s.progressCalled = true
+	s.progressReported = read
+	s.cfg.progress(read, total)
+}
@@ -0,0 +1,108 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestProgress(t *testing.T) {
+	ctx := context.Background()
+	content := make([]byte, 200<<10)
+	rand.New(rand.NewSource(1)).Read(content)
+	size := int64(len(content))
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	tests := []struct {
+		name string
+		read func(t *testing.T, opt Option) []byte
+	}{
+		{
+			name: "read",
+			read: func(t *testing.T, opt Option) []byte {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, opt)
+				defer rsc.Close()
+				var got []byte
+				buf := make([]byte, 4<<10)
+				for {
+					n, err := rsc.Read(buf)
+					got = append(got, buf[:n]...)
+					if err == io.EOF {
+						return got
+					}
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			},
+		},
+		{
+			name: "write to",
+			read: func(t *testing.T, opt Option) []byte {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, opt, WithMaxBytesPerRead(4<<10))
+				defer rsc.Close()
+				var got bytes.Buffer
+				if _, err := rsc.WriteTo(&got); err != nil {
+					t.Fatal(err)
+				}
+				return got.Bytes()
+			},
+		},
+		{
+			name: "transport",
+			read: func(t *testing.T, opt Option) []byte {
+				client := &http.Client{Transport: NewMustReaderTransportWithOptions(s.Client().Transport, nil, opt, WithMaxBytesPerRead(4<<10))}
+				resp, err := client.Get(s.URL)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer resp.Body.Close()
+				got, err := io.ReadAll(resp.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				return got
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls [][2]int64
+			got := tt.read(t, WithProgress(func(readBytes, totalBytes int64) {
+				calls = append(calls, [2]int64{readBytes, totalBytes})
+			}))
+			if !bytes.Equal(got, content) {
+				t.Fatalf("got %d bytes that differ from the content", len(got))
+			}
+			if len(calls) == 0 || calls[len(calls)-1] != [2]int64{size, size} {
+				t.Fatalf("got progress %v, want it to end with %d of %d", calls, size, size)
+			}
+			var last int64
+			for i, call := range calls {
+				if call[1] != size || i < len(calls)-1 && call[0]-last < progressInterval {
+					t.Fatalf("got progress %v, want calls at least %d bytes apart with the size", calls, progressInterval)
+				}
+				last = call[0]
+			}
+			if limit := size/progressInterval + 1; int64(len(calls)) > limit {
+				t.Fatalf("got %d progress calls, want at most %d", len(calls), limit)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+)
+
+// maxUnboundedRange is the largest range ReadRanges allocates for before the size of the content is known.
+const maxUnboundedRange = 64 << 20
+
+// ReadRanges reads the given byte ranges with a single multi-range request, without moving the offset
+// used by Read and Seek or touching the open body, and returns their bytes in the order of ranges.
+//
+// The multipart/byteranges response may answer with its parts in any order, and with overlapping
+// or nearby ranges coalesced into one part. Ranges the response does not cover, such as when the
+// server answers with a single range or the full content, are read with their own range requests.
+// Ranges past the end of the content are cut short, in which case ReadRanges also returns io.EOF.
+// A range over 64 MiB, such as one ending at math.MaxInt64, first makes ReadRanges learn the size of the content,
+// and fails if it cannot be learned.
+func (s *Seeker) ReadRanges(ctx context.Context, ranges []Range) ([][]byte, error) {
+	for _, r := range ranges {
+		if r.Start < 0 || r.End < r.Start {
+			return nil, fmt.Errorf("invalid range [%d, %d)", r.Start, r.End)
+		}
+	}
+	if s.closed.Load() {
+		return nil, s.closedError("read")
+	}
+	defer s.flushEvents()
+
+	size := s.Size()
+	if size < 0 {
+		for _, r := range ranges {
+			if r.End-r.Start > maxUnboundedRange {
+				// A range this large is only read once the size bounds it.
+				if err := s.discoverSize(ctx); err != nil {
+					return nil, fmt.Errorf("range [%d, %d) of content of unknown size: %w", r.Start, r.End, err)
+				}
+				size = s.Size()
+				break
+			}
+		}
+	}
+	bufs := make([][]byte, len(ranges))
+	var pending []int
+	for i, r := range ranges {
+		end := r.End
+		if size >= 0 {
+			end = max(min(end, size), r.Start)
+		}
+		bufs[i] = make([]byte, end-r.Start)
+		if end > r.Start {
+			pending = append(pending, i)
+		}
+	}
+
+	if len(pending) > 1 {
+		var err error
+		pending, err = s.readMultipart(ctx, ranges, bufs, pending)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, i := range pending {
+		n, err := s.fetch(ctx, bufs[i], ranges[i].Start)
+		bufs[i] = bufs[i][:n]
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+	}
+
+	for i, r := range ranges {
+		if int64(len(bufs[i])) < r.End-r.Start {
+			return bufs, io.EOF
+		}
+	}
+	return bufs, nil
+}
+
+// readMultipart requests the pending ranges at once, fills those the response covers,
+// and returns the ones left to be read on their own.
+func (s *Seeker) readMultipart(ctx context.Context, ranges []Range, bufs [][]byte, pending []int) ([]int, error) {
+	specs := make([]string, 0, len(pending))
+	for _, i := range pending {
+		specs = append(specs, fmt.Sprintf("%d-%d", ranges[i].Start, ranges[i].Start+int64(len(bufs[i]))-1))
+	}
+	traceCtx, trace := withTrace(ctx)
+	req := s.req.Clone(traceCtx)
+	req.Header.Set("Range", "bytes="+strings.Join(specs, ","))
+	s.mu.Lock()
+	if s.rangesUnsupported {
+		s.mu.Unlock()
+		return pending, nil
+	}
+	s.pinHeaders(req)
+	s.setIfRange(req)
+	s.stats.Requests++
+	attempt := s.stats.Requests
+	s.mu.Unlock()
+
+	resp, req, chain, err := s.roundTrip(req)
+	timing := trace.finish()
+	s.mu.Lock()
+	if trace != nil {
+		s.timings.add(timing)
+	}
+	if err != nil {
+		s.recordAttempt(AttemptInfo{Attempt: attempt, Offset: ranges[pending[0]].Start, Ranged: true, Err: err, Hops: chain, Timing: timing})
+		s.cfg.debugDump.dump(attempt, req, nil, err)
+		s.mu.Unlock()
+		return nil, err
+	}
+	s.recordAttempt(AttemptInfo{Attempt: attempt, Offset: ranges[pending[0]].Start, Ranged: true, StatusCode: resp.StatusCode, Hops: chain, Timing: timing})
+	if resp.StatusCode != http.StatusPartialContent {
+		// The full content, or a refusal, is left to the requests of the single ranges to handle.
+		s.cfg.debugDump.dump(attempt, req, resp, nil)
+		s.mu.Unlock()
+		drainBody(resp)
+		return pending, nil
+	}
+	s.recordAcceptRanges(resp.Header)
+	s.mu.Unlock()
+	defer resp.Body.Close()
+
+	filled := make([]bool, len(ranges))
+	if encoding := contentEncoding(resp.Header); encoding != "" {
+		return nil, &EncodedRangeError{Offset: ranges[pending[0]].Start, Encoding: encoding}
+	}
+	mediaType, params, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "multipart/byteranges" {
+		// A single range, such as all ranges coalesced into one or only the first one.
+		err := s.readPart(req, resp, resp.Header.Get("Content-Type"), resp.Header.Get(contentRangeKey), resp.Body, ranges, bufs, filled)
+		if err != nil {
+			return nil, err
+		}
+		return unfilled(pending, filled), nil
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, fmt.Errorf("%w: multipart/byteranges without a boundary", ErrContentRangeParse)
+	}
+	mr := multipart.NewReader(resp.Body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		err = s.readPart(req, resp, part.Header.Get("Content-Type"), part.Header.Get(contentRangeKey), part, ranges, bufs, filled)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return unfilled(pending, filled), nil
+}
+
+// readPart reads the bytes of one part of a range response described by contentRange
+// and copies them into every range not yet filled that the part covers entirely.
+// The part is streamed through a pooled buffer, so a part must lie within the requested ranges
+// and the known size rather than be trusted to a single allocation.
+func (s *Seeker) readPart(req *http.Request, resp *http.Response, contentType, contentRange string, body io.Reader, ranges []Range, bufs [][]byte, filled []bool) error {
+	if contentRange == "" {
+		return ErrNoContentRange
+	}
+	start, end, size, err := parseContentRange(contentRange, s.cfg.strictContentRange)
+	if err != nil {
+		return err
+	}
+	if end < start {
+		return fmt.Errorf("%w: range ends before it starts: %s", ErrContentRangeParse, contentRange)
+	}
+	lo, hi := int64(-1), int64(-1)
+	for i, r := range ranges {
+		if len(bufs[i]) > 0 {
+			if lo < 0 || r.Start < lo {
+				lo = r.Start
+			}
+			hi = max(hi, r.Start+int64(len(bufs[i])))
+		}
+	}
+	if start < lo || end >= hi {
+		return fmt.Errorf("%w: part outside the requested ranges: %s", ErrRangeOffsetMismatch, contentRange)
+	}
+
+	s.mu.Lock()
+	err = s.checkSizeAt(start, resp, size)
+	if err == nil && s.size >= 0 && end >= s.size {
+		err = fmt.Errorf("%w: range ends past the end of the content: %s", ErrSizeMismatch, contentRange)
+	}
+	if err == nil {
+		err = s.checkResponseAs(start, req, resp, contentType)
+	}
+	if err == nil && s.size < 0 && size >= 0 {
+		s.setSize(size)
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var covered []int
+	for i, r := range ranges {
+		if !filled[i] && r.Start >= start && r.Start+int64(len(bufs[i])) <= end+1 {
+			covered = append(covered, i)
+		}
+	}
+	bufp := getBuffer(int(min(end-start+1, int64(bufferClasses[len(bufferClasses)-1]))))
+	defer putBuffer(bufp)
+	buf := *bufp
+	for pos := start; pos <= end; {
+		n, err := io.ReadFull(body, buf[:min(int64(len(buf)), end+1-pos)])
+		// Bytes between the covered ranges are read and dropped.
+		for _, i := range covered {
+			r := ranges[i]
+			from, to := max(pos, r.Start), min(pos+int64(n), r.Start+int64(len(bufs[i])))
+			if from < to {
+				copy(bufs[i][from-r.Start:to-r.Start], buf[from-pos:to-pos])
+			}
+		}
+		pos += int64(n)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+	}
+	for _, i := range covered {
+		filled[i] = true
+	}
+	return nil
+}
+
+// unfilled returns the pending ranges that are not filled.
+func unfilled(pending []int, filled []bool) []int {
+	var left []int
+	for _, i := range pending {
+		if !filled[i] {
+			left = append(left, i)
+		}
+	}
+	return left
+}
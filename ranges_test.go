@@ -0,0 +1,250 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newMultiRangeServer returns a server answering multi-range requests with a multipart/byteranges response
+// whose parts coalesce ranges less than 8 bytes apart and come in reverse order.
+// If single is set, it answers with the first range only, as a single 206.
+func newMultiRangeServer(content []byte, single bool, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.Header().Set("Content-Type", "application/octet-stream")
+		spec := strings.TrimPrefix(r.Header.Get("Range"), "bytes=")
+		if spec == "" {
+			w.Write(content)
+			return
+		}
+		var ranges []Range
+		for _, s := range strings.Split(spec, ",") {
+			var start, end int64
+			if _, err := fmt.Sscanf(s, "%d-%d", &start, &end); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ranges = append(ranges, Range{Start: start, End: min(end+1, int64(len(content)))})
+		}
+		if single || len(ranges) == 1 {
+			r := ranges[0]
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write(content[r.Start:r.End])
+			return
+		}
+
+		sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+		coalesced := ranges[:1]
+		for _, r := range ranges[1:] {
+			last := &coalesced[len(coalesced)-1]
+			if r.Start <= last.End+8 {
+				last.End = max(last.End, r.End)
+			} else {
+				coalesced = append(coalesced, r)
+			}
+		}
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		for i := len(coalesced) - 1; i >= 0; i-- {
+			r := coalesced[i]
+			part, _ := mw.CreatePart(textproto.MIMEHeader{
+				"Content-Type":  {"application/octet-stream"},
+				"Content-Range": {fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End-1, len(content))},
+			})
+			part.Write(content[r.Start:r.End])
+		}
+		mw.Close()
+		w.Header().Set("Content-Type", "multipart/byteranges; boundary="+mw.Boundary())
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(body.Bytes())
+	}))
+}
+
+func TestReadRanges(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 10)
+	ranges := []Range{{50, 60}, {0, 5}, {3, 12}, {90, 100}, {20, 22}}
+
+	tests := []struct {
+		name         string
+		server       func(requests *int32) *httptest.Server
+		wantRequests int32
+	}{
+		{
+			name: "coalesced",
+			server: func(requests *int32) *httptest.Server {
+				return newMultiRangeServer(content, false, requests)
+			},
+			wantRequests: 1,
+		},
+		{
+			name: "single",
+			server: func(requests *int32) *httptest.Server {
+				return newMultiRangeServer(content, true, requests)
+			},
+			// The first range is served by the multi-range request, the other four on their own.
+			wantRequests: 5,
+		},
+		{
+			name: "serve content",
+			server: func(requests *int32) *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(requests, 1)
+					http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+				}))
+			},
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int32
+			s := tt.server(&requests)
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req)
+			defer rsc.Close()
+
+			got, err := rsc.ReadRanges(ctx, ranges)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := make([][]byte, len(ranges))
+			for i, r := range ranges {
+				want[i] = content[r.Start:r.End]
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+			if requests != tt.wantRequests {
+				t.Fatalf("got %d requests, want %d", requests, tt.wantRequests)
+			}
+			if rsc.Size() != int64(len(content)) {
+				t.Fatalf("got size %d, want %d", rsc.Size(), len(content))
+			}
+			if rsc.Offset() != 0 {
+				t.Fatalf("got offset %d, want 0", rsc.Offset())
+			}
+		})
+	}
+}
+
+func TestReadRangesPastEnd(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 10)
+	var requests int32
+	s := newMultiRangeServer(content, false, &requests)
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	got, err := rsc.ReadRanges(ctx, []Range{{10, 20}, {95, 110}, {40, 40}})
+	if err != io.EOF {
+		t.Fatalf("got error %v, want io.EOF", err)
+	}
+	if want := [][]byte{content[10:20], content[95:], {}}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestReadRangesOversizedPart(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A Content-Range far larger than both the requested ranges and the body.
+		w.Header().Set("Content-Range", "bytes 10-9223372036854775805/*")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte("0123456789"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	_, err = rsc.ReadRanges(ctx, []Range{{10, 20}, {30, 40}})
+	if !errors.Is(err, ErrRangeOffsetMismatch) {
+		t.Fatalf("got error %v, want ErrRangeOffsetMismatch", err)
+	}
+}
+
+func TestReadRangesOpenEnded(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 10)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		wantErr bool
+	}{
+		{
+			name: "size learned",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+			},
+		},
+		{
+			name: "size unknown",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				// Flushing before the body is written sends it chunked, without a Content-Length.
+				w.WriteHeader(http.StatusOK)
+				w.(http.Flusher).Flush()
+				w.Write(content)
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(tt.handler)
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req)
+			defer rsc.Close()
+
+			got, err := rsc.ReadRanges(ctx, []Range{{90, math.MaxInt64}, {0, 10}})
+			if tt.wantErr {
+				if err == nil || err == io.EOF {
+					t.Fatalf("got error %v, want a failure to learn the size", err)
+				}
+				return
+			}
+			if err != io.EOF {
+				t.Fatalf("got error %v, want io.EOF", err)
+			}
+			if want := [][]byte{content[90:], content[:10]}; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
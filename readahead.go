@@ -0,0 +1,124 @@
+package httpseek
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var errReadAheadClosed = errors.New("read-ahead closed")
+
+// readAheadBody reads a body into a ring buffer from a goroutine, ahead of the Reads that drain it.
+type readAheadBody struct {
+	rc     io.ReadCloser
+	budget *MemoryBudget
+	done   chan struct{}
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	buf  []byte
+	// The buffered bytes are the n bytes from start, wrapping around the end of buf.
+	start int
+	n     int
+	// err is the error of the body, returned once the buffered bytes are drained.
+	err    error
+	closed bool
+}
+
+// readAhead wraps r in a readAheadBody if WithReadAhead is set and the memory budget grants a buffer.
+func (s *Seeker) readAhead(r io.ReadCloser) io.ReadCloser {
+	if s.cfg.readAhead <= 0 {
+		return r
+	}
+	n := s.cfg.memoryBudget.acquire(BufferReadAhead, s.cfg.readAhead)
+	if n == 0 {
+		return r
+	}
+	b := &readAheadBody{
+		rc:     r,
+		budget: s.cfg.memoryBudget,
+		done:   make(chan struct{}),
+		buf:    make([]byte, n),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	go b.fill()
+	return b
+}
+
+// fill reads the body into the free space of the buffer until the body fails or the buffer is closed.
+func (b *readAheadBody) fill() {
+	defer close(b.done)
+	for {
+		b.mu.Lock()
+		for b.n == len(b.buf) && !b.closed {
+			b.cond.Wait()
+		}
+		if b.closed {
+			b.mu.Unlock()
+			return
+		}
+		if b.n == 0 {
+			b.start = 0
+		}
+		// Read delivers only the buffered bytes, so the free space after them is written without the lock.
+		end := (b.start + b.n) % len(b.buf)
+		free := b.buf[end:]
+		if end < b.start {
+			free = b.buf[end:b.start]
+		}
+		b.mu.Unlock()
+
+		m, err := b.rc.Read(free)
+
+		b.mu.Lock()
+		b.n += m
+		b.err = err
+		b.cond.Broadcast()
+		b.mu.Unlock()
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Read copies buffered bytes into p, waiting for the goroutine if there are none.
+func (b *readAheadBody) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.n == 0 && b.err == nil && !b.closed {
+		b.cond.Wait()
+	}
+	if b.n == 0 {
+		if b.closed {
+			return 0, errReadAheadClosed
+		}
+		return 0, b.err
+	}
+	k := copy(p[:min(len(p), b.n)], b.buf[b.start:min(b.start+b.n, len(b.buf))])
+	if k < len(p) && k < b.n {
+		k += copy(p[k:min(len(p), b.n)], b.buf)
+	}
+	b.start = (b.start + k) % len(b.buf)
+	b.n -= k
+	b.cond.Broadcast()
+	return k, nil
+}
+
+// Close stops the goroutine, closing the body to abort a read in flight, and gives the buffer back to the budget.
+func (b *readAheadBody) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	b.cond.Broadcast()
+	b.mu.Unlock()
+	err := b.rc.Close()
+	<-b.done
+	b.budget.release(int64(len(b.buf)))
+	return err
+}
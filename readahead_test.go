@@ -0,0 +1,209 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+// countingBody counts the bytes read from a response body.
+type countingBody struct {
+	io.ReadCloser
+	read   atomic.Int64
+	closed atomic.Bool
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read.Add(int64(n))
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.closed.Store(true)
+	return b.ReadCloser.Close()
+}
+
+// countingTransport wraps the bodies of the responses of base in a countingBody.
+type countingTransport struct {
+	base   http.RoundTripper
+	mu     sync.Mutex
+	bodies []*countingBody
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body := &countingBody{ReadCloser: resp.Body}
+	resp.Body = body
+	t.mu.Lock()
+	t.bodies = append(t.bodies, body)
+	t.mu.Unlock()
+	return resp, nil
+}
+
+func (t *countingTransport) body(i int) *countingBody {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.bodies[i]
+}
+
+// waitRead waits until n bytes were read from b.
+func waitRead(t *testing.T, b *countingBody, n int64) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for b.read.Load() < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d bytes read from the body, want %d", b.read.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestReadAhead(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	transport := &countingTransport{base: testutil.NewReplayTransport(content)}
+	budget := NewMemoryBudget(200)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithReadAhead(100), WithMemoryBudget(budget))
+	defer rsc.Close()
+
+	buf := make([]byte, 10)
+	if _, err := io.ReadFull(rsc, buf); err != nil {
+		t.Fatal(err)
+	}
+	// The goroutine fills the buffer ahead of the 10 bytes read, and no further.
+	body := transport.body(0)
+	waitRead(t, body, 110)
+	time.Sleep(10 * time.Millisecond)
+	if n := body.read.Load(); n != 110 {
+		t.Fatalf("got %d bytes read from the body, want 110", n)
+	}
+	if used := budget.Used(); used != 100 {
+		t.Fatalf("got %d bytes of the budget used, want 100", used)
+	}
+
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := append(buf, rest...); !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+
+	if err := rsc.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !body.closed.Load() {
+		t.Fatal("body not closed by Close")
+	}
+	if used := budget.Used(); used != 0 {
+		t.Fatalf("got %d bytes of the budget used after Close, want 0", used)
+	}
+}
+
+func TestReadAheadSeek(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	transport := &countingTransport{base: testutil.NewReplayTransport(content)}
+	budget := NewMemoryBudget(200)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithReadAhead(100), WithMemoryBudget(budget))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(500, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	// The Seek stopped the prefetch of the first body before the next Read opens another.
+	if !transport.body(0).closed.Load() {
+		t.Fatal("body not closed by Seek")
+	}
+	if used := budget.Used(); used != 0 {
+		t.Fatalf("got %d bytes of the budget used after Seek, want 0", used)
+	}
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content[500:]) {
+		t.Fatalf("got %q, want %q", got, content[500:])
+	}
+}
+
+func TestReadAheadError(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	transport := testutil.NewReplayTransport(content, testutil.ServeThenFail(250, errors.New("connection reset")))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithReadAhead(100))
+	defer rsc.Close()
+
+	// The error of the body is returned after the 250 bytes before it, and the next Read resumes there.
+	buf := make([]byte, 300)
+	n, err := io.ReadFull(rsc, buf)
+	var readErr *ReadError
+	if n != 250 || !errors.As(err, &readErr) || readErr.Offset != 250 {
+		t.Fatalf("got %d bytes and error %v, want 250 bytes and a read error at 250", n, err)
+	}
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := append(buf[:n], rest...)
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	requests := transport.Requests()
+	if len(requests) != 2 || requests[1].Range != "bytes=250-" {
+		t.Fatalf("got requests %+v, want a resume at 250", requests)
+	}
+}
+
+func TestReadAheadNoBudget(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	transport := testutil.NewReplayTransport(content)
+	budget := NewMemoryBudget(200)
+	budget.acquire(BufferCache, 200)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithReadAhead(100), WithMemoryBudget(budget))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rsc.rc.(*readAheadBody); ok {
+		t.Fatal("got a read-ahead buffer without budget left")
+	}
+}
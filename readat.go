@@ -0,0 +1,41 @@
+package httpseek
+
+import (
+	"errors"
+	"io"
+)
+
+// ReadAt reads len(p) bytes at off with its own bounded range request,
+// without moving the offset used by Read and Seek or touching the open body.
+// Responses are checked against the size and validators learned by earlier requests.
+//
+// ReadAt may be called concurrently, also with Read and Seek. It fills p unless the content ends first,
+// in which case it returns the bytes up to the end with io.EOF.
+func (s *Seeker) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if s.closed.Load() {
+		return 0, s.closedError("read")
+	}
+	defer s.flushEvents()
+	if size := s.Size(); size >= 0 {
+		if off >= size {
+			return 0, io.EOF
+		}
+		if s.cfg.blockSize > 0 {
+			return s.readAtBlocks(p, off)
+		}
+		if rest := size - off; int64(len(p)) > rest {
+			n, err := s.fetch(s.ctx, p[:rest], off)
+			if err == nil {
+				err = io.EOF
+			}
+			return n, err
+		}
+	}
+	if s.cfg.blockSize > 0 {
+		return s.readAtBlocks(p, off)
+	}
+	return s.fetch(s.ctx, p, off)
+}
@@ -0,0 +1,81 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReadAt(t *testing.T) {
+	ctx := context.Background()
+
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	head := make([]byte, 10)
+	if _, err := io.ReadFull(rsc, head); err != nil {
+		t.Fatal(err)
+	}
+
+	var rest []byte
+	var restErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		rest, restErr = io.ReadAll(rsc)
+	}()
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(off int64) {
+			defer wg.Done()
+			buf := make([]byte, 37)
+			n, err := rsc.ReadAt(buf, off)
+			if err != nil {
+				t.Errorf("ReadAt(%d): %v", off, err)
+				return
+			}
+			if !bytes.Equal(buf[:n], content[off:off+37]) {
+				t.Errorf("ReadAt(%d): got %q", off, buf[:n])
+			}
+		}(int64(i * 59))
+	}
+	wg.Wait()
+
+	if restErr != nil {
+		t.Fatal(restErr)
+	}
+	if !bytes.Equal(append(head, rest...), content) {
+		t.Fatal("stream content differs after ReadAt")
+	}
+
+	buf := make([]byte, 20)
+	n, err := rsc.ReadAt(buf, int64(len(content)-5))
+	if err != io.EOF || n != 5 || string(buf[:n]) != "56789" {
+		t.Fatalf("got %d, %v, %q at the end", n, err, buf[:n])
+	}
+	if _, err := rsc.ReadAt(buf, int64(len(content))); err != io.EOF {
+		t.Fatalf("got %v past the end, want %v", err, io.EOF)
+	}
+
+	content = content[:500]
+	if _, err := rsc.ReadAt(buf, 100); !errors.Is(err, ErrSizeChanged) {
+		t.Fatalf("got %v, want %v", err, ErrSizeChanged)
+	}
+}
@@ -1,6 +1,7 @@
 package httpseek
 
 import (
+	"errors"
 	"io"
 )
 
@@ -11,6 +12,7 @@ type mustReader struct {
 }
 
 // NewMustReader returns a reader that will retry reading with partial byte ranges if the underlying reader returns an error.
+// It gives up on ErrNotResumable and on a Content-Range that cannot be parsed or has an inconsistent size.
 func NewMustReader(rsc io.ReadSeeker, errorHandler func(int, error) error) io.Reader {
 	return &mustReader{
 		rsc:          rsc,
@@ -20,15 +22,37 @@ func NewMustReader(rsc io.ReadSeeker, errorHandler func(int, error) error) io.Re
 
 // NewMustReadCloser returns a reader that will retry reading with partial byte ranges if the underlying reader returns an error.
 func NewMustReadCloser(rsc io.ReadSeekCloser, errorHandler func(int, error) error) io.ReadCloser {
-	return struct {
-		io.Reader
-		io.Closer
-	}{
-		Reader: NewMustReader(rsc, errorHandler),
+	return &mustReadCloser{
+		mustReader: &mustReader{
+			rsc:          rsc,
+			errorHandler: errorHandler,
+		},
 		Closer: rsc,
 	}
 }
 
+type mustReadCloser struct {
+	*mustReader
+	io.Closer
+}
+
+// CurrentAttempt returns the attempt that produced the bytes currently being read,
+// if the underlying reader reports it.
+func (r *mustReader) CurrentAttempt() AttemptInfo {
+	if reporter, ok := r.rsc.(attemptReporter); ok {
+		return reporter.CurrentAttempt()
+	}
+	return AttemptInfo{}
+}
+
+// IsComplete reports whether the wrapped reader delivered the whole content.
+func (r *mustReader) IsComplete() bool {
+	if reporter, ok := r.rsc.(completeReporter); ok {
+		return reporter.IsComplete()
+	}
+	return false
+}
+
 // Read reads from the reader.
 func (r *mustReader) Read(p []byte) (n int, err error) {
 	return r.read(0, p)
@@ -41,7 +65,7 @@ func (r *mustReader) read(retry int, p []byte) (n int, err error) {
 		return n, nil
 	}
 
-	if err == io.EOF {
+	if err == io.EOF || errors.Is(err, ErrNotResumable) || isMalformedRange(err) {
 		return n, err
 	}
 
@@ -56,3 +80,9 @@ func (r *mustReader) read(retry int, p []byte) (n int, err error) {
 	}
 	return r.read(retry+1, p)
 }
+
+// isMalformedRange reports whether err is a Content-Range that cannot be parsed or has an inconsistent size,
+// which retrying is unlikely to fix, unlike ErrRangeOffsetMismatch.
+func isMalformedRange(err error) bool {
+	return errors.Is(err, ErrContentRangeParse) || errors.Is(err, ErrSizeMismatch)
+}
@@ -3,6 +3,7 @@ package httpseek
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -63,3 +64,126 @@ func (l *errorResponseWriter) Header() http.Header {
 func (l *errorResponseWriter) WriteHeader(statusCode int) {
 	l.rw.WriteHeader(statusCode)
 }
+
+func TestMustReadNotResumable(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		handler func(w http.ResponseWriter, r *http.Request)
+	}{
+		{
+			name: "range ignored",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("Hello World!"))
+			},
+		},
+		{
+			name: "size changed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World, again!")))
+			},
+		},
+		{
+			name: "content type changed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+			},
+		},
+		{
+			name: "content location changed",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Location", "/other")
+				http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Range") != "" {
+					tt.handler(w, r)
+					return
+				}
+				w.Header().Set("Content-Type", "text/plain")
+				w.Header().Set("Content-Location", "/test")
+				http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+			}))
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeeker(ctx, s.Client().Transport, req)
+			defer rsc.Close()
+
+			var retries int
+			r := NewMustReader(rsc, func(retry int, err error) error {
+				retries++
+				return nil
+			})
+			if _, err := io.ReadFull(r, make([]byte, 5)); err != nil {
+				t.Fatal(err)
+			}
+			rsc.Release()
+
+			_, err = io.ReadAll(r)
+			if !errors.Is(err, ErrNotResumable) {
+				t.Fatalf("got %v, want %v", err, ErrNotResumable)
+			}
+			if retries != 0 {
+				t.Fatalf("got %d retries, want none", retries)
+			}
+		})
+	}
+}
+
+func TestMustReadMalformedRange(t *testing.T) {
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		contentRange string
+		want         error
+	}{
+		{contentRange: "bytes five-eleven/12", want: ErrContentRangeParse},
+		{contentRange: "bytes 5-12/12", want: ErrSizeMismatch},
+	} {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Range") != "" {
+				w.Header().Set("Content-Range", test.contentRange)
+				w.WriteHeader(http.StatusPartialContent)
+				w.Write([]byte(" World!"))
+				return
+			}
+			http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+		}))
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeeker(ctx, s.Client().Transport, req)
+
+		var retries int
+		r := NewMustReader(rsc, func(retry int, err error) error {
+			retries++
+			return nil
+		})
+		if _, err := io.ReadFull(r, make([]byte, 5)); err != nil {
+			t.Fatal(err)
+		}
+		rsc.Release()
+
+		_, err = io.ReadAll(r)
+		if !errors.Is(err, test.want) {
+			t.Fatalf("%q: got %v, want %v", test.contentRange, err, test.want)
+		}
+		if retries != 0 {
+			t.Fatalf("%q: got %d retries, want none", test.contentRange, retries)
+		}
+		rsc.Close()
+		s.Close()
+	}
+}
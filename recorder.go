@@ -0,0 +1,52 @@
+package httpseek
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recorder receives the requests, bytes and retries of a Seeker, such as to export them as metrics.
+// Its methods are called synchronously, possibly from several goroutines at once, and must not block.
+type Recorder interface {
+	// Request is called when a request, after its redirects, got its final response or failed.
+	// offset is the first byte requested, 0 without a Range and -1 for a suffix range;
+	// status is 0 if no response was received; dur includes the redirects.
+	Request(offset int64, status int, dur time.Duration)
+	// BytesRead is called with the number of bytes of content delivered by each Read.
+	BytesRead(n int)
+	// Retry is called before each request retried after err, as counted by Stats.Retries.
+	Retry(err error)
+}
+
+// CacheRecorder is implemented by a Recorder that also receives the bytes served from a cache.
+type CacheRecorder interface {
+	// CacheHit is called with the number of bytes each Read or ReadAt copied from the blocks
+	// of WithCache or WithBlockAlignment instead of requesting them.
+	CacheHit(n int)
+}
+
+type nopRecorder struct{}
+
+func (nopRecorder) Request(int64, int, time.Duration) {}
+func (nopRecorder) BytesRead(int)                     {}
+func (nopRecorder) Retry(error)                       {}
+
+// requestOffset returns the first byte requested by the Range header of req,
+// 0 without a Range and -1 for a suffix range.
+func requestOffset(req *http.Request) int64 {
+	spec, ok := strings.CutPrefix(req.Header.Get("Range"), "bytes=")
+	if !ok {
+		return 0
+	}
+	first, _, _ := strings.Cut(spec, "-")
+	if first == "" {
+		return -1
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(first), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return offset
+}
@@ -0,0 +1,100 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+type recordedRequest struct {
+	offset int64
+	status int
+}
+
+type testRecorder struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+	bytes    int
+	retries  []error
+}
+
+func (r *testRecorder) Request(offset int64, status int, dur time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, recordedRequest{offset, status})
+}
+
+func (r *testRecorder) BytesRead(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.bytes += n
+}
+
+func (r *testRecorder) Retry(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries = append(r.retries, err)
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := testutil.NewReplayTransport(content,
+		testutil.Respond(http.StatusTooManyRequests),
+		testutil.ServeThenFail(5, errors.New("connection reset")),
+	)
+	rec := &testRecorder{}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithRetry(3, time.Millisecond), WithRecorder(rec))
+	defer rsc.Close()
+
+	data, err := io.ReadAll(NewMustReader(rsc, nil))
+	if err != nil || string(data) != string(content) {
+		t.Fatalf("got %q, %v, want %q", data, err, content)
+	}
+	tail, err := rsc.Tail(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tail.Close()
+
+	want := []recordedRequest{{0, http.StatusTooManyRequests}, {0, http.StatusOK}, {5, http.StatusPartialContent}, {-1, http.StatusPartialContent}}
+	if !reflect.DeepEqual(rec.requests, want) {
+		t.Errorf("got requests %v, want %v", rec.requests, want)
+	}
+	if rec.bytes != len(content) {
+		t.Errorf("got %d bytes, want %d", rec.bytes, len(content))
+	}
+	var statusErr *StatusError
+	if len(rec.retries) != 1 || !errors.As(rec.retries[0], &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("got retries %v, want one after a 429", rec.retries)
+	}
+}
+
+func TestRecorderTransport(t *testing.T) {
+	content := []byte("Hello World!")
+	rec := &testRecorder{}
+	client := &http.Client{Transport: NewMustReaderTransportWithOptions(testutil.NewReplayTransport(content), nil, WithRecorder(rec))}
+	resp, err := client.Get("http://example.com/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if _, err := io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+	if want := []recordedRequest{{0, http.StatusOK}}; !reflect.DeepEqual(rec.requests, want) || rec.bytes != len(content) {
+		t.Fatalf("got requests %v and %d bytes, want %v and %d", rec.requests, rec.bytes, want, len(content))
+	}
+}
@@ -0,0 +1,234 @@
+package httpseek
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultMaxRedirects = 10
+
+// maxRedirectDrain bounds how much of a redirect response body is read
+// so that its connection can be reused; larger bodies are just closed.
+const maxRedirectDrain = 4 << 10
+
+// ErrTooManyRedirects is matched by errors.Is when a request is redirected more often than allowed.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// credentialHeaders are dropped from a request redirected to another host.
+var credentialHeaders = []string{
+	"Authorization",
+	"Www-Authenticate",
+	"Cookie",
+	"Cookie2",
+}
+
+// maxRedirectChain bounds the hops recorded for one attempt.
+const maxRedirectChain = 32
+
+// Hop describes one response received while following redirects.
+type Hop struct {
+	// URL is the URL that was requested, without any user information.
+	URL string
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Header is the response header, with security-sensitive values redacted.
+	Header http.Header
+}
+
+func newHop(req *http.Request, resp *http.Response) Hop {
+	return Hop{
+		URL:        req.URL.Redacted(),
+		StatusCode: resp.StatusCode,
+		Header:     redactHeader(resp.Header),
+	}
+}
+
+// RedirectChain returns the hops taken by the most recent attempt, in order, ending with the final response.
+func (s *Seeker) RedirectChain() []Hop {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.attempts) == 0 {
+		return nil
+	}
+	return append([]Hop(nil), s.attempts[len(s.attempts)-1].Hops...)
+}
+
+// TooManyRedirectsError is returned when a request is still redirected after the maximum number of redirects.
+type TooManyRedirectsError struct {
+	// Redirects is the number of redirects followed.
+	Redirects int
+	// Location is the Location header of the last redirect response, which was not followed.
+	Location string
+	// Chain is the hops taken, ending with the redirect that was not followed.
+	Chain []Hop
+}
+
+func (e *TooManyRedirectsError) Error() string {
+	return fmt.Sprintf("stopped after %d redirects, next Location %q", e.Redirects, e.Location)
+}
+
+func (e *TooManyRedirectsError) Is(target error) bool {
+	return target == ErrTooManyRedirects
+}
+
+// RedirectError is returned when a redirect cannot be followed.
+type RedirectError struct {
+	// Location is the Location header of the redirect.
+	Location string
+	// Chain is the hops taken, ending with the redirect that could not be followed.
+	Chain []Hop
+	// Err is the reason.
+	Err error
+}
+
+func (e *RedirectError) Error() string {
+	return fmt.Sprintf("failed to follow redirect to %q: %v", e.Location, e.Err)
+}
+
+func (e *RedirectError) Unwrap() error {
+	return e.Err
+}
+
+// drainBody reads up to maxRedirectDrain bytes of an unused response body, such as that of a redirect, and closes it.
+func drainBody(resp *http.Response) {
+	_, _ = discardN(resp.Body, maxRedirectDrain)
+	_ = resp.Body.Close()
+}
+
+// keepsCredentials reports whether a request to host u, redirected from the request to initial,
+// may carry the credential headers.
+func (c *config) keepsCredentials(initial, u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if isDomainOrSubdomain(host, strings.ToLower(initial.Hostname())) {
+		return true
+	}
+	for _, trusted := range c.trustedHosts {
+		if isDomainOrSubdomain(host, strings.ToLower(trusted)) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDomainOrSubdomain reports whether sub is parent or a subdomain of it.
+func isDomainOrSubdomain(sub, parent string) bool {
+	if sub == parent {
+		return true
+	}
+	return strings.HasSuffix(sub, "."+parent)
+}
+
+func isRedirect(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// roundTrip sends req and follows redirects unless disabled.
+// It returns the final response together with the request that produced it and the hops taken.
+func (s *Seeker) roundTrip(req *http.Request) (*http.Response, *http.Request, []Hop, error) {
+	resp, final, chain, err := roundTrip(&s.cfg, req, s.sendHop)
+	if resp != nil {
+		s.observeVersion(resp)
+	}
+	return resp, final, chain, err
+}
+
+// sendHop sends one hop of a request of s, counting it and the bytes of its body.
+func (s *Seeker) sendHop(req *http.Request, hop int) (*http.Response, error) {
+	s.requestCount.Add(1)
+	resp, err := s.send(s.cfg.traceHop(req, hop))
+	if err != nil {
+		return nil, err
+	}
+	if resp.Body != nil && resp.Body != http.NoBody {
+		resp.Body = &fetchedBody{ReadCloser: resp.Body, n: &s.bytesFetched}
+	}
+	return resp, nil
+}
+
+// roundTrip sends req with send and follows redirects as configured by cfg,
+// reporting the request to the Recorder and the logger of cfg.
+func roundTrip(cfg *config, req *http.Request, send func(req *http.Request, hop int) (*http.Response, error)) (*http.Response, *http.Request, []Hop, error) {
+	start := time.Now()
+	resp, final, chain, err := followRedirects(cfg, req, send)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	dur := time.Since(start)
+	cfg.recorder.Request(requestOffset(req), status, dur)
+	if cfg.logger != nil {
+		cfg.logRequest(req, final, resp, err, dur)
+	}
+	return resp, final, chain, err
+}
+
+// followRedirects sends req with send, then the requests its redirects lead to, unless disabled.
+func followRedirects(cfg *config, req *http.Request, send func(req *http.Request, hop int) (*http.Response, error)) (*http.Response, *http.Request, []Hop, error) {
+	var chain []Hop
+	initial := req.URL
+	if cfg.identityEncoding {
+		// Ranges then count bytes of the content itself, whatever the server does with compressed ones.
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+	for redirects := 0; ; redirects++ {
+		if err := cfg.prepare(req); err != nil {
+			return nil, req, chain, err
+		}
+		// Signing comes last, once nothing else changes the request of this hop.
+		if err := cfg.sign(req); err != nil {
+			return nil, req, chain, err
+		}
+		resp, err := send(req, redirects)
+		if err != nil {
+			return nil, req, chain, &transportError{err: err}
+		}
+		if len(chain) < maxRedirectChain {
+			chain = append(chain, newHop(req, resp))
+		}
+
+		if !cfg.followRedirects || cfg.maxRedirects <= 0 || !isRedirect(resp.StatusCode) {
+			return resp, req, chain, nil
+		}
+		location := resp.Header.Get("Location")
+		if location == "" {
+			return resp, req, chain, nil
+		}
+		if redirects >= cfg.maxRedirects {
+			drainBody(resp)
+			return nil, req, chain, &TooManyRedirectsError{Redirects: redirects, Location: location, Chain: chain}
+		}
+
+		u, err := req.URL.Parse(location)
+		drainBody(resp)
+		if err != nil {
+			return nil, req, chain, &RedirectError{Location: location, Chain: chain, Err: err}
+		}
+		if cfg.redirectPolicy != nil {
+			if err := cfg.redirectPolicy(req, u); err != nil {
+				return nil, req, chain, &RedirectError{Location: location, Chain: chain, Err: err}
+			}
+		}
+		if cfg.logger != nil {
+			cfg.logRedirect(req, resp, u)
+		}
+
+		next := req.Clone(req.Context())
+		next.URL = u
+		next.Host = ""
+		if !cfg.keepsCredentials(initial, u) {
+			for _, key := range credentialHeaders {
+				next.Header.Del(key)
+			}
+		}
+		req = next
+	}
+}
@@ -0,0 +1,380 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newRedirectServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/target" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+}
+
+func TestFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+
+	s := newRedirectServer()
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/source", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err = io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+}
+
+func TestNoFollowRedirects(t *testing.T) {
+	ctx := context.Background()
+
+	s := newRedirectServer()
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/source", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithFollowRedirects(false))
+	defer rsc.Close()
+
+	resp, err := rsc.Response()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusFound || resp.Header.Get("Location") != "/target" {
+		t.Fatalf("got %d to %q, want %d to %q", resp.StatusCode, resp.Header.Get("Location"), http.StatusFound, "/target")
+	}
+	if rsc.Size() != -1 {
+		t.Fatalf("got size %d, want %d", rsc.Size(), -1)
+	}
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(got, []byte("/target")) {
+		t.Fatalf("got %q, want the redirect body", got)
+	}
+}
+
+func TestMaxRedirects(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hop, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+		if hop < 3 {
+			http.Redirect(w, r, "/"+strconv.Itoa(hop+1), http.StatusFound)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	for _, tt := range []struct {
+		max     int
+		status  int
+		wantErr bool
+	}{
+		{max: 3, status: http.StatusOK},
+		{max: 2, wantErr: true},
+		{max: 0, status: http.StatusFound},
+	} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/0", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithMaxRedirects(tt.max))
+		resp, err := rsc.Response()
+		if tt.wantErr {
+			var redirectsErr *TooManyRedirectsError
+			if !errors.Is(err, ErrTooManyRedirects) || !errors.As(err, &redirectsErr) || redirectsErr.Location != "/3" || len(redirectsErr.Chain) != 3 {
+				t.Fatalf("max %d: got %v, want too many redirects towards /3", tt.max, err)
+			}
+			rsc.Close()
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Fatalf("max %d: got status %d, want %d", tt.max, resp.StatusCode, tt.status)
+		}
+		rsc.Close()
+	}
+}
+
+func TestRedirectChain(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/target" {
+			w.Header().Set("Age", "42")
+			w.Header().Set("Set-Cookie", "session=secret")
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/source", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(rsc); err != nil {
+		t.Fatal(err)
+	}
+
+	chain := rsc.RedirectChain()
+	if len(chain) != 2 {
+		t.Fatalf("got %d hops, want 2", len(chain))
+	}
+	first, last := chain[0], chain[1]
+	if first.URL != s.URL+"/source" || first.StatusCode != http.StatusFound {
+		t.Fatalf("got first hop %s %d, want %s %d", first.URL, first.StatusCode, s.URL+"/source", http.StatusFound)
+	}
+	if first.Header.Get("Location") != "/target" || first.Header.Get("Age") != "42" {
+		t.Fatalf("got first hop header %v, want Location and Age kept", first.Header)
+	}
+	if got := first.Header.Get("Set-Cookie"); got != "REDACTED" {
+		t.Fatalf("got Set-Cookie %q, want it redacted", got)
+	}
+	if last.URL != s.URL+"/target" || last.StatusCode != http.StatusPartialContent {
+		t.Fatalf("got last hop %s %d, want %s %d", last.URL, last.StatusCode, s.URL+"/target", http.StatusPartialContent)
+	}
+
+	attempts := rsc.Attempts()
+	if len(attempts) != 1 || len(attempts[0].Hops) != 2 {
+		t.Fatalf("got attempts %+v, want one with its hops", attempts)
+	}
+}
+
+func TestRedirectBodiesClosed(t *testing.T) {
+	ctx := context.Background()
+
+	var mut sync.Mutex
+	states := map[net.Conn]http.ConnState{}
+	newServer := func(h http.HandlerFunc) *httptest.Server {
+		s := httptest.NewUnstartedServer(h)
+		s.Config.ConnState = func(c net.Conn, state http.ConnState) {
+			mut.Lock()
+			defer mut.Unlock()
+			states[c] = state
+		}
+		s.Start()
+		return s
+	}
+	redirect := func(to string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Location", to)
+			w.WriteHeader(http.StatusFound)
+			w.Write(bytes.Repeat([]byte("x"), 1024))
+		}
+	}
+
+	content := bytes.Repeat([]byte("0123456789"), 10000)
+	origin := newServer(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	})
+	defer origin.Close()
+	edge := newServer(redirect(origin.URL + "/file"))
+	defer edge.Close()
+	front := newServer(redirect(edge.URL + "/file"))
+	defer front.Close()
+
+	transport := &http.Transport{}
+	defer transport.CloseIdleConnections()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, front.URL+"/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, transport, req)
+	buf := make([]byte, 10)
+	for _, off := range []int64{0, 5000, 20000} {
+		if _, err := rsc.Seek(off, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.ReadFull(rsc, buf); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf, content[off:off+10]) {
+			t.Fatalf("at %d got %q, want %q", off, buf, content[off:off+10])
+		}
+	}
+	rsc.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		mut.Lock()
+		var open int
+		for _, state := range states {
+			if state == http.StateNew || state == http.StateActive {
+				open++
+			}
+		}
+		mut.Unlock()
+		if open == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d connections still open or active", open)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRedirectCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	var gotAuth, gotCookie string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotCookie = r.Header.Get("Authorization"), r.Header.Get("Cookie")
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer target.Close()
+	// The same server under another host name.
+	crossHost := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+
+	for _, tt := range []struct {
+		name     string
+		location string
+		opts     []Option
+		keep     bool
+	}{
+		{name: "same host", location: target.URL + "/file", keep: true},
+		{name: "cross host", location: crossHost + "/file", keep: false},
+		{name: "trusted host", location: crossHost + "/file", opts: []Option{WithTrustedHosts("localhost")}, keep: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gotAuth, gotCookie = "", ""
+			origin := httptest.NewServer(http.RedirectHandler(tt.location, http.StatusFound))
+			defer origin.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Authorization", "Bearer token")
+			req.Header.Set("Cookie", "session=secret")
+			rsc := NewSeekerWithOptions(ctx, origin.Client().Transport, req, tt.opts...)
+			defer rsc.Close()
+
+			got, err := io.ReadAll(rsc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "Hello World!" {
+				t.Fatalf("got %q, want %q", got, "Hello World!")
+			}
+			if (gotAuth == "Bearer token") != tt.keep || (gotCookie == "session=secret") != tt.keep {
+				t.Fatalf("got Authorization %q and Cookie %q, want kept %v", gotAuth, gotCookie, tt.keep)
+			}
+		})
+	}
+}
+
+func TestRedirectPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/source":
+			http.Redirect(w, r, "/forbidden", http.StatusFound)
+		case "/target":
+			http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/source", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errForbidden := errors.New("forbidden")
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithRedirectPolicy(func(prev *http.Request, next *url.URL) error {
+		return errForbidden
+	}))
+	_, err = rsc.Read(make([]byte, 1))
+	var redirectErr *RedirectError
+	if !errors.Is(err, errForbidden) || !errors.As(err, &redirectErr) || redirectErr.Location != "/forbidden" {
+		t.Fatalf("got %v, want the policy error for /forbidden", err)
+	}
+	rsc.Close()
+
+	var calls int
+	rsc = NewSeekerWithOptions(ctx, s.Client().Transport, req, WithRedirectPolicy(func(prev *http.Request, next *url.URL) error {
+		calls++
+		if prev.URL.Path != "/source" {
+			t.Errorf("got previous path %q, want %q", prev.URL.Path, "/source")
+		}
+		next.Path = "/target"
+		return nil
+	}))
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err = io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+	if calls != 2 {
+		t.Fatalf("got %d policy calls, want one per request", calls)
+	}
+}
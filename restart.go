@@ -0,0 +1,62 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ErrNeedsRestart is returned when resuming keeps failing at the same offset
+// and the Seeker is configured to leave the restart to the caller.
+var ErrNeedsRestart = errors.New("resume keeps failing at the same offset, transfer needs to restart")
+
+// RestartMode selects what happens when resuming keeps failing at the same offset.
+type RestartMode int
+
+const (
+	// RestartFromStart re-requests the content from offset 0 and discards the bytes already delivered.
+	RestartFromStart RestartMode = iota
+	// RestartReport returns ErrNeedsRestart, for callers that can restart the transfer more cheaply themselves.
+	RestartReport
+)
+
+// resumeFailed records a failed resume at offset
+// and reports whether the configured restart threshold has been reached.
+func (s *Seeker) resumeFailed(offset int64) bool {
+	if s.cfg.restartAfter <= 0 || offset == 0 {
+		return false
+	}
+	if s.resumeFailOffset != offset {
+		s.resumeFailOffset = offset
+		s.resumeFailures = 0
+	}
+	s.resumeFailures++
+	return s.resumeFailures >= s.cfg.restartAfter
+}
+
+// restart handles a resume that has failed too often at offset according to the restart mode.
+func (s *Seeker) restart(ctx context.Context, offset int64, cause error) (io.ReadCloser, int64, *http.Response, error) {
+	s.resumeFailures = 0
+	if s.cfg.restartMode == RestartReport {
+		return nil, -1, nil, fmt.Errorf("%w: %w", ErrNeedsRestart, cause)
+	}
+
+	s.mu.Lock()
+	s.stats.Restarts++
+	s.mu.Unlock()
+	r, size, resp, err := s.open(ctx, 0, -1)
+	if err != nil {
+		return nil, -1, nil, err
+	}
+	s.mu.Lock()
+	s.stats.WastedBytes += offset
+	s.mu.Unlock()
+	err = discard(r, offset)
+	if err != nil {
+		_ = r.Close()
+		return nil, -1, nil, err
+	}
+	return r, size, resp, nil
+}
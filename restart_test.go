@@ -0,0 +1,75 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRestartAfter(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") != "" {
+			w.WriteHeader(http.StatusRequestTimeout)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	newSeeker := func(mode RestartMode) *Seeker {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewSeekerWithOptions(ctx, s.Client().Transport, req, WithRetry(0, 0), WithRestartAfter(2, mode))
+	}
+
+	t.Run("from start", func(t *testing.T) {
+		rsc := newSeeker(RestartFromStart)
+		defer rsc.Close()
+
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rsc.Read(make([]byte, 1)); err == nil {
+			t.Fatal("expected the first resume to fail")
+		}
+		got, err := io.ReadAll(rsc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "World!" {
+			t.Fatalf("got %q, want %q", got, "World!")
+		}
+		if rsc.Stats().Restarts != 1 {
+			t.Fatalf("got %d restarts, want %d", rsc.Stats().Restarts, 1)
+		}
+	})
+
+	t.Run("report", func(t *testing.T) {
+		rsc := newSeeker(RestartReport)
+		defer rsc.Close()
+
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rsc.Read(make([]byte, 1)); err == nil || errors.Is(err, ErrNeedsRestart) {
+			t.Fatalf("got %v, want plain failure", err)
+		}
+		_, err := rsc.Read(make([]byte, 1))
+		if !errors.Is(err, ErrNeedsRestart) {
+			t.Fatalf("got %v, want %v", err, ErrNeedsRestart)
+		}
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) {
+			t.Fatalf("got %v, want the cause to be kept", err)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+const defaultChunkSize = 4 << 20
+
+// ErrDigestMismatch is matched by errors.Is when a restored chunk, or the content read through NewVerifyingReader,
+// does not match its expected digest.
+var ErrDigestMismatch = errors.New("digest mismatch")
+
+// ChunkError is returned by RestoreTo for the chunk [Start, End) that could not be restored.
+type ChunkError struct {
+	Start int64
+	End   int64
+	Err   error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("failed to restore bytes %d-%d: %v", e.Start, e.End-1, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// RestoreOption configures RestoreTo.
+type RestoreOption func(*restoreConfig)
+
+type restoreConfig struct {
+	chunkSize    int64
+	parallel     int
+	newHash      func() hash.Hash
+	chunkDigest  func(start, end int64) []byte
+	checkpointer Checkpointer
+}
+
+// WithChunkSize sets the size of the chunks fetched by RestoreTo; the default is 4 MiB.
+// Chunks are aligned to multiples of n from the start of the content.
+func WithChunkSize(n int64) RestoreOption {
+	return func(c *restoreConfig) {
+		c.chunkSize = n
+	}
+}
+
+// WithParallel sets how many chunks RestoreTo fetches at once; the default is 1.
+func WithParallel(n int) RestoreOption {
+	return func(c *restoreConfig) {
+		c.parallel = n
+	}
+}
+
+// WithChunkDigest verifies each chunk [start, end) against the digest returned by digest, such as from a manifest,
+// computed with newHash, or SHA-256 if newHash is nil. A chunk for which digest returns nil is not verified.
+// A mismatching chunk is fetched again and fails with ErrDigestMismatch if it keeps mismatching.
+func WithChunkDigest(newHash func() hash.Hash, digest func(start, end int64) []byte) RestoreOption {
+	return func(c *restoreConfig) {
+		if newHash == nil {
+			newHash = sha256.New
+		}
+		c.newHash = newHash
+		c.chunkDigest = digest
+	}
+}
+
+// WithCheckpointer makes RestoreTo skip the chunks that c reports as completed
+// and mark each chunk complete once it has been written and verified.
+func WithCheckpointer(c Checkpointer) RestoreOption {
+	return func(rc *restoreConfig) {
+		rc.checkpointer = c
+	}
+}
+
+// RestoreTo fetches the whole content in chunks with bounded range requests and writes each at its offset in w,
+// leaving the open stream untouched. A chunk that fails while being read or verified is fetched again up to the
+// retry count of the Seeker. RestoreTo stops at the first chunk that cannot be restored and returns a ChunkError;
+// with a checkpointer, the chunks restored until then stay recorded so that the next call fetches only the rest.
+func (s *Seeker) RestoreTo(ctx context.Context, w io.WriterAt, opts ...RestoreOption) error {
+	cfg := restoreConfig{
+		chunkSize: defaultChunkSize,
+		parallel:  1,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.chunkSize <= 0 {
+		return errors.New("chunk size must be positive")
+	}
+	cfg.parallel = max(cfg.parallel, 1)
+
+	size := s.Size()
+	if size < 0 {
+		if err := s.discoverSize(ctx); err != nil {
+			return err
+		}
+		size = s.Size()
+	}
+
+	done := NewExtentSet()
+	if cfg.checkpointer != nil {
+		for _, r := range cfg.checkpointer.Completed() {
+			done.Add(r.Start, r.End)
+		}
+	}
+	var chunks []Range
+	for start := int64(0); start < size; start += cfg.chunkSize {
+		end := min(start+cfg.chunkSize, size)
+		if !done.Contains(start, end) {
+			chunks = append(chunks, Range{Start: start, End: end})
+		}
+	}
+
+	workCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		next     = make(chan Range)
+	)
+	for i := 0; i < min(cfg.parallel, len(chunks)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, min(cfg.chunkSize, size))
+			for c := range next {
+				if err := s.restoreChunk(workCtx, w, &cfg, c, buf, done); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					return
+				}
+			}
+		}()
+	}
+feed:
+	for _, c := range chunks {
+		select {
+		case next <- c:
+		case <-workCtx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if missing := done.Missing(0, size); len(missing) > 0 {
+		return &ChunkError{Start: missing[0].Start, End: missing[0].End, Err: errors.New("chunk was not restored")}
+	}
+	return nil
+}
+
+// restoreChunk fetches, verifies, writes and checkpoints the chunk c, using buf as scratch space,
+// and adds it to done.
+func (s *Seeker) restoreChunk(ctx context.Context, w io.WriterAt, cfg *restoreConfig, c Range, buf []byte, done *ExtentSet) error {
+	buf = buf[:c.End-c.Start]
+	var err error
+	for retry := 0; retry <= s.cfg.retries; retry++ {
+		var r io.ReadCloser
+		r, err = s.fetchReader(ctx, c.Start, int64(len(buf)))
+		if err != nil {
+			// Already retried by the request itself.
+			break
+		}
+		_, err = io.ReadFull(r, buf)
+		_ = r.Close()
+		if err == nil {
+			err = verifyChunk(cfg, c, buf)
+		}
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		s.mu.Lock()
+		s.stats.Retries++
+		s.mu.Unlock()
+	}
+	if err == nil {
+		_, err = w.WriteAt(buf, c.Start)
+	}
+	if err == nil && cfg.checkpointer != nil {
+		err = cfg.checkpointer.MarkComplete(c.Start, c.End)
+	}
+	if err != nil {
+		return &ChunkError{Start: c.Start, End: c.End, Err: err}
+	}
+	done.Add(c.Start, c.End)
+	return nil
+}
+
+func verifyChunk(cfg *restoreConfig, c Range, data []byte) error {
+	if cfg.chunkDigest == nil {
+		return nil
+	}
+	want := cfg.chunkDigest(c.Start, c.End)
+	if want == nil {
+		return nil
+	}
+	h := cfg.newHash()
+	h.Write(data)
+	if !bytes.Equal(h.Sum(nil), want) {
+		return ErrDigestMismatch
+	}
+	return nil
+}
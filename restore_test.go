@@ -0,0 +1,96 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type memWriterAt struct {
+	mut sync.Mutex
+	buf []byte
+}
+
+func (w *memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+	copy(w.buf[off:], p)
+	return len(p), nil
+}
+
+func TestRestoreTo(t *testing.T) {
+	ctx := context.Background()
+
+	content := make([]byte, 1000)
+	rand.New(rand.NewSource(1)).Read(content)
+	digest := func(start, end int64) []byte {
+		sum := sha256.Sum256(content[start:end])
+		return sum[:]
+	}
+
+	var corrupt atomic.Bool
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		data := content
+		if corrupt.Load() && r.Header.Get("Range") == "bytes=300-399" {
+			data = bytes.Clone(content)
+			data[350] ^= 0xff
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(data))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpointer, err := NewFileCheckpointer(filepath.Join(t.TempDir(), "checkpoint.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := []RestoreOption{
+		WithChunkSize(100),
+		WithChunkDigest(nil, digest),
+		WithCheckpointer(checkpointer),
+	}
+
+	corrupt.Store(true)
+	dst := &memWriterAt{buf: make([]byte, len(content))}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithExpectedSize(int64(len(content))))
+	err = rsc.RestoreTo(ctx, dst, opts...)
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || !errors.Is(err, ErrDigestMismatch) || chunkErr.Start != 300 || chunkErr.End != 400 {
+		t.Fatalf("got %v, want a digest mismatch for bytes 300-399", err)
+	}
+	if got, want := checkpointer.Completed(), []Range{{Start: 0, End: 300}}; len(got) != 1 || got[0] != want[0] {
+		t.Fatalf("got completed %v, want %v", got, want)
+	}
+	rsc.Close()
+
+	corrupt.Store(false)
+	requests.Store(0)
+	rsc = NewSeekerWithOptions(ctx, s.Client().Transport, req, WithExpectedSize(int64(len(content))))
+	defer rsc.Close()
+	if err := rsc.RestoreTo(ctx, dst, append(opts, WithParallel(4))...); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(dst.buf, content) {
+		t.Fatal("restored content differs")
+	}
+	if got := requests.Load(); got != 7 {
+		t.Fatalf("got %d requests, want one per missing chunk", got)
+	}
+	if got := checkpointer.Completed(); len(got) != 1 || got[0] != (Range{Start: 0, End: 1000}) {
+		t.Fatalf("got completed %v, want the whole content", got)
+	}
+}
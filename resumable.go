@@ -0,0 +1,44 @@
+package httpseek
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotResumable is matched by errors.Is when the Seeker has concluded that no resume at the current offset can succeed,
+// so the caller has to restart the whole transfer or give up. It is reported when resuming at a non-zero offset fails because
+//   - the server answered the ranged request with the full content, so it no longer supports ranges (ErrCodeForByteRange),
+//   - the server answered the ranged request with a Content-Encoding (ErrEncodedRange),
+//   - the content changed since the first response (ErrContentChanged, ContentTypeChangedError),
+//   - the total size changed, including shrinking below the offset (ErrSizeChanged).
+var ErrNotResumable = errors.New("transfer cannot be resumed")
+
+// NotResumableError wraps the failure that made resuming impossible.
+type NotResumableError struct {
+	// Offset is the offset at which resuming failed.
+	Offset int64
+	// Err is the failure.
+	Err error
+}
+
+func (e *NotResumableError) Error() string {
+	return fmt.Sprintf("cannot resume at offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *NotResumableError) Unwrap() error {
+	return e.Err
+}
+
+func (e *NotResumableError) Is(target error) bool {
+	return target == ErrNotResumable
+}
+
+// isNotResumable reports whether a failed resume cannot succeed by retrying at the same offset.
+func isNotResumable(err error) bool {
+	var contentTypeErr *ContentTypeChangedError
+	return errors.Is(err, ErrCodeForByteRange) ||
+		errors.Is(err, ErrEncodedRange) ||
+		errors.Is(err, ErrContentChanged) ||
+		errors.Is(err, ErrSizeChanged) ||
+		errors.As(err, &contentTypeErr)
+}
@@ -0,0 +1,367 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff = 30 * time.Second
+)
+
+// StatusError is returned when a request is answered with a status that cannot be used as content.
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+	// Header is the header of the response.
+	Header http.Header
+
+	// retry is set for statuses configured with StatusRetryAfterDelay.
+	retry bool
+	// serverError is set for server errors retried as enabled by WithServerErrorRetry.
+	serverError bool
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// RetryAfter returns the delay requested by the Retry-After header of the response,
+// given in seconds or as an HTTP date, or 0 if there is none.
+func (e *StatusError) RetryAfter() time.Duration {
+	return retryAfter(e.Header)
+}
+
+// ErrRetriesExhausted is matched by errors.Is when the retry budget ran out.
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// RetriesExhaustedError is returned when a retryable failure is not retried
+// because the retry count or the elapsed-time budget ran out.
+type RetriesExhaustedError struct {
+	// Retries is the number of retries made.
+	Retries int
+	// Elapsed is the time since the first attempt of the transfer.
+	Elapsed time.Duration
+	// Err is the error of the last attempt.
+	Err error
+}
+
+func (e *RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("retries exhausted after %d retries in %s: %v", e.Retries, e.Elapsed.Round(time.Millisecond), e.Err)
+}
+
+func (e *RetriesExhaustedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *RetriesExhaustedError) Is(target error) bool {
+	return target == ErrRetriesExhausted
+}
+
+// RetryReason classifies why a failed attempt may be retried.
+type RetryReason int
+
+const (
+	// RetryNone means the failure is not retryable.
+	RetryNone RetryReason = iota
+	// RetryTransport means the request failed before a response was received.
+	RetryTransport
+	// RetryRequestTimeout means the server answered 408 Request Timeout.
+	RetryRequestTimeout
+	// RetryTooEarly means the server answered 425 Too Early.
+	// The retry is sent as a regular request; net/http never sends TLS early data.
+	RetryTooEarly
+	// RetryStatus means the status is configured with StatusRetryAfterDelay.
+	RetryStatus
+	// RetryRangeMismatch means the Content-Range of the response does not cover the requested offset
+	// (ErrRangeOffsetMismatch).
+	RetryRangeMismatch
+	// RetryServerError means the server answered 500, 502, 503 or 504,
+	// retried as enabled by WithServerErrorRetry.
+	RetryServerError
+	// RetryTooManyRequests means the server answered 429 Too Many Requests.
+	RetryTooManyRequests
+	// RetryPrepare means the preparer set by WithRequestPreparer failed (PrepareError).
+	RetryPrepare
+	// RetryAttemptTimeout means the request got no response within the timeout of WithRequestTimeout
+	// (ErrAttemptTimeout).
+	RetryAttemptTimeout
+)
+
+func (r RetryReason) String() string {
+	switch r {
+	case RetryTransport:
+		return "transport"
+	case RetryRequestTimeout:
+		return "request timeout"
+	case RetryTooEarly:
+		return "too early"
+	case RetryStatus:
+		return "status"
+	case RetryRangeMismatch:
+		return "range mismatch"
+	case RetryServerError:
+		return "server error"
+	case RetryTooManyRequests:
+		return "too many requests"
+	case RetryPrepare:
+		return "prepare"
+	case RetryAttemptTimeout:
+		return "attempt timeout"
+	}
+	return "none"
+}
+
+// RetryPhase tells whether any byte of the content had been delivered when a retry was considered.
+type RetryPhase int
+
+const (
+	// RetryPhaseInitial means no byte has been delivered yet,
+	// so the failure may be permanent, such as a bad URL.
+	RetryPhaseInitial RetryPhase = iota
+	// RetryPhaseProgress means bytes have already been delivered, so the transfer is known to work.
+	RetryPhaseProgress
+)
+
+func (p RetryPhase) String() string {
+	if p == RetryPhaseProgress {
+		return "progress"
+	}
+	return "initial"
+}
+
+// RetryInfo describes a failed attempt that is about to be retried.
+type RetryInfo struct {
+	// Retry is the number of the retry, starting at 1.
+	Retry int
+	// Offset is the offset of the failed request.
+	Offset int64
+	// Reason is the classification of the failure.
+	Reason RetryReason
+	// StatusCode is the HTTP status code of the failed response, or 0 if there is none.
+	StatusCode int
+	// Phase tells whether any byte had been delivered before the failure.
+	Phase RetryPhase
+	// Delay is how long the Seeker waits before retrying.
+	Delay time.Duration
+	// RetryAfter is the delay requested by the Retry-After header of the failed response,
+	// before it was capped by WithMaxRetryAfter, or 0 if there is none.
+	RetryAfter time.Duration
+	// Err is the error of the failed attempt.
+	Err error
+}
+
+// classifyRetry returns why err may be retried, or RetryNone.
+func classifyRetry(err error) RetryReason {
+	if errors.Is(err, ErrAttemptTimeout) {
+		// A TimeoutError also matches context.DeadlineExceeded.
+		return RetryAttemptTimeout
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return RetryNone
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.retry {
+			return RetryStatus
+		}
+		if statusErr.serverError {
+			return RetryServerError
+		}
+		switch statusErr.StatusCode {
+		case http.StatusRequestTimeout:
+			return RetryRequestTimeout
+		case http.StatusTooEarly:
+			return RetryTooEarly
+		case http.StatusTooManyRequests:
+			return RetryTooManyRequests
+		}
+		return RetryNone
+	}
+
+	var prepareErr *PrepareError
+	if errors.As(err, &prepareErr) {
+		return RetryPrepare
+	}
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return RetryTransport
+	}
+	if errors.Is(err, ErrRangeOffsetMismatch) {
+		return RetryRangeMismatch
+	}
+	return RetryNone
+}
+
+// transportError marks an error returned by the RoundTripper.
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// open issues the request for length bytes at offset, or the rest of the content if length is negative,
+// retrying failures that are classified as retryable.
+func (s *Seeker) open(ctx context.Context, offset int64, length int64) (io.ReadCloser, int64, *http.Response, error) {
+	s.mu.Lock()
+	if s.transferStart.IsZero() {
+		s.transferStart = time.Now()
+	}
+	transferStart, progressed := s.transferStart, s.progressed
+	s.mu.Unlock()
+	var r io.ReadCloser
+	var size int64
+	var resp *http.Response
+	err := retryLoop(ctx, &s.cfg, offset, transferStart, !progressed, func() error {
+		var err error
+		r, size, resp, err = s.reader(ctx, offset, length)
+		return err
+	}, func() {
+		s.mu.Lock()
+		s.stats.Retries++
+		s.mu.Unlock()
+	})
+	if err != nil {
+		return nil, -1, nil, err
+	}
+	return r, size, resp, nil
+}
+
+// retryLoop calls attempt until it succeeds, or fails in a way cfg does not retry, for a request at offset.
+// The transfer began at transferStart, and initial reports that none of its bytes were delivered yet,
+// for WithFastFail. retried, if not nil, is called before sleeping for each retry.
+//
+// With a maximum elapsed time, a backoff that would end past the budget is truncated
+// so the final attempt is made when the budget expires; no attempt is started after it.
+func retryLoop(ctx context.Context, cfg *config, offset int64, transferStart time.Time, initial bool, attempt func() error, retried func()) error {
+	phase, retries, backoff := RetryPhaseProgress, cfg.retries, cfg.retryBackoff
+	if cfg.maxElapsed > 0 && !cfg.retriesSet {
+		// The time budget alone bounds retrying.
+		retries = math.MaxInt
+	}
+	if initial {
+		phase = RetryPhaseInitial
+		if cfg.fastFail {
+			retries, backoff = cfg.fastRetries, cfg.fastBackoff
+		}
+	}
+	// Server errors are counted against their own budget.
+	var retry, serverRetry int
+	for {
+		err := attempt()
+		if err == nil {
+			return nil
+		}
+
+		reason := classifyRetry(err)
+		err = unwrapTransportError(err)
+		if reason == RetryNone {
+			return err
+		}
+		n, limit, delay := &retry, retries, backoff
+		if reason == RetryServerError {
+			n, limit, delay = &serverRetry, cfg.serverErrorRetries, cfg.serverErrorBackoff
+		}
+		*n++
+		elapsed := time.Since(transferStart)
+		overBudget := cfg.maxElapsed > 0 && elapsed >= cfg.maxElapsed
+		if *n > limit || overBudget {
+			if cfg.logger != nil {
+				cfg.logGiveUp(ctx, offset, retry+serverRetry-1, err)
+			}
+			if retry+serverRetry == 1 && cfg.maxElapsed == 0 {
+				return err
+			}
+			if overBudget {
+				err = &TimeoutError{Kind: ErrBudgetExceeded, Timeout: cfg.maxElapsed, Offset: offset, Err: err}
+			}
+			return &RetriesExhaustedError{
+				Retries: retry + serverRetry - 1,
+				Elapsed: elapsed,
+				Err:     err,
+			}
+		}
+
+		info := RetryInfo{
+			Retry:  retry + serverRetry,
+			Offset: offset,
+			Reason: reason,
+			Phase:  phase,
+			Delay:  backoffDelay(delay, *n),
+			Err:    err,
+		}
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			info.StatusCode = statusErr.StatusCode
+			if d := retryAfter(statusErr.Header); d > 0 {
+				info.RetryAfter = d
+				info.Delay = min(d, cfg.maxRetryAfter)
+			}
+		}
+		if cfg.maxElapsed > 0 && elapsed+info.Delay > cfg.maxElapsed {
+			info.Delay = cfg.maxElapsed - elapsed
+		}
+		if cfg.retryHandler != nil {
+			if err := cfg.retryHandler(info); err != nil {
+				return err
+			}
+		}
+
+		if retried != nil {
+			retried()
+		}
+		cfg.recorder.Retry(err)
+		if cfg.logger != nil {
+			cfg.logRetry(ctx, info)
+		}
+		if err := sleep(ctx, info.Delay); err != nil {
+			return err
+		}
+	}
+}
+
+func unwrapTransportError(err error) error {
+	if transportErr, ok := err.(*transportError); ok {
+		return transportErr.err
+	}
+	return err
+}
+
+// backoffDelay returns the delay before the given retry, doubling from the initial delay.
+func backoffDelay(initial time.Duration, retry int) time.Duration {
+	d := initial
+	for i := 1; i < retry && d < defaultMaxBackoff; i++ {
+		d *= 2
+	}
+	if d > defaultMaxBackoff {
+		d = defaultMaxBackoff
+	}
+	return d
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
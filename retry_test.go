@@ -0,0 +1,495 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestRetryStatus(t *testing.T) {
+	ctx := context.Background()
+
+	for _, status := range []int{http.StatusRequestTimeout, http.StatusTooEarly} {
+		transport := testutil.NewReplayTransport([]byte("Hello World!"),
+			testutil.Respond(status),
+			testutil.Respond(status),
+		)
+
+		var infos []RetryInfo
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, transport, req,
+			WithRetry(2, time.Millisecond),
+			WithRetryHandler(func(info RetryInfo) error {
+				infos = append(infos, info)
+				return nil
+			}),
+		)
+
+		got, err := io.ReadAll(rsc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "Hello World!" {
+			t.Fatalf("got %q, want %q", got, "Hello World!")
+		}
+
+		if len(infos) != 2 {
+			t.Fatalf("got %d retries, want %d", len(infos), 2)
+		}
+		for i, info := range infos {
+			if info.Retry != i+1 || info.StatusCode != status {
+				t.Fatalf("got retry %d status %d, want retry %d status %d", info.Retry, info.StatusCode, i+1, status)
+			}
+			if info.Delay != time.Millisecond<<i {
+				t.Fatalf("got delay %s, want %s", info.Delay, time.Millisecond<<i)
+			}
+		}
+		want := RetryRequestTimeout
+		if status == http.StatusTooEarly {
+			want = RetryTooEarly
+		}
+		if infos[0].Reason != want {
+			t.Fatalf("got reason %s, want %s", infos[0].Reason, want)
+		}
+		if requests := transport.Requests(); len(requests) != 3 {
+			t.Fatalf("got %d requests, want %d", len(requests), 3)
+		}
+		rsc.Close()
+	}
+}
+
+func TestRetryExhausted(t *testing.T) {
+	ctx := context.Background()
+
+	transport := testutil.NewReplayTransport([]byte("Hello World!"),
+		testutil.Respond(http.StatusRequestTimeout),
+		testutil.Respond(http.StatusRequestTimeout),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithRetry(1, time.Millisecond))
+	defer rsc.Close()
+
+	_, err = io.ReadAll(rsc)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusRequestTimeout {
+		t.Fatalf("got %v, want status error %d", err, http.StatusRequestTimeout)
+	}
+	if rsc.Stats().Requests != 2 {
+		t.Fatalf("got %d requests, want %d", rsc.Stats().Requests, 2)
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	ctx := context.Background()
+
+	transport := testutil.NewReplayTransport([]byte("Hello World!"),
+		testutil.Respond(http.StatusRequestTimeout),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Without WithRetry, a retryable failure is returned as is.
+	rsc := NewSeeker(ctx, transport, req)
+	defer rsc.Close()
+
+	_, err = io.ReadAll(rsc)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusRequestTimeout {
+		t.Fatalf("got %v, want status error %d", err, http.StatusRequestTimeout)
+	}
+	if rsc.Stats().Requests != 1 || rsc.Stats().Retries != 0 {
+		t.Fatalf("got %d requests and %d retries, want 1 and none", rsc.Stats().Requests, rsc.Stats().Retries)
+	}
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestTimeout)
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req,
+		WithRetry(1000, 20*time.Millisecond),
+		WithMaxElapsed(100*time.Millisecond),
+	)
+	defer rsc.Close()
+
+	start := time.Now()
+	_, err = io.ReadAll(rsc)
+	elapsed := time.Since(start)
+
+	var exhaustedErr *RetriesExhaustedError
+	if !errors.As(err, &exhaustedErr) || !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("got %v, want %v", err, ErrRetriesExhausted)
+	}
+	var timeoutErr *TimeoutError
+	if !errors.As(err, &timeoutErr) || timeoutErr.Kind != ErrBudgetExceeded || timeoutErr.Timeout != 100*time.Millisecond {
+		t.Fatalf("got %v, want %v", err, ErrBudgetExceeded)
+	}
+	if !errors.Is(err, ErrBudgetExceeded) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want it to match %v and %v", err, ErrBudgetExceeded, context.DeadlineExceeded)
+	}
+	if exhaustedErr.Elapsed < 100*time.Millisecond {
+		t.Fatalf("got elapsed %s, want at least the budget", exhaustedErr.Elapsed)
+	}
+	if elapsed > time.Second {
+		t.Fatalf("retrying took %s, want it bounded by the budget", elapsed)
+	}
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("got %v, want the last cause to be wrapped", err)
+	}
+}
+
+func TestRetryMaxElapsedOnly(t *testing.T) {
+	ctx := context.Background()
+
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusRequestTimeout)
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithMaxElapsed(500*time.Millisecond))
+	defer rsc.Close()
+
+	_, err = io.ReadAll(rsc)
+	if !errors.Is(err, ErrRetriesExhausted) || !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("got %v, want %v", err, ErrBudgetExceeded)
+	}
+	// Backoffs of 100ms, 200ms and the 200ms left of the budget, with no retry count to stop earlier.
+	if got := requests.Load(); got < 4 {
+		t.Fatalf("got %d requests, want at least 4", got)
+	}
+}
+
+func TestRetryFastFail(t *testing.T) {
+	ctx := context.Background()
+
+	var failing bool
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing || r.Header.Get("Range") == "" && r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusRequestTimeout)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	var phases []RetryPhase
+	options := []Option{
+		WithRetry(3, time.Millisecond),
+		WithFastFail(1, time.Millisecond),
+		WithRetryHandler(func(info RetryInfo) error {
+			phases = append(phases, info.Phase)
+			return nil
+		}),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/broken", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, options...)
+	_, err = io.ReadAll(rsc)
+	if !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("got %v, want %v", err, ErrRetriesExhausted)
+	}
+	if rsc.Stats().Requests != 2 {
+		t.Fatalf("got %d requests, want the fast budget of %d", rsc.Stats().Requests, 2)
+	}
+	rsc.Close()
+
+	phases = nil
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc = NewSeekerWithOptions(ctx, s.Client().Transport, req, options...)
+	defer rsc.Close()
+	if _, err := io.ReadFull(rsc, make([]byte, 6)); err != nil {
+		t.Fatal(err)
+	}
+	failing = true
+	if _, err := rsc.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Read(make([]byte, 1)); !errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("got %v, want %v", err, ErrRetriesExhausted)
+	}
+	if rsc.Stats().Requests != 5 {
+		t.Fatalf("got %d requests, want the long-haul budget of %d", rsc.Stats().Requests, 5)
+	}
+	for _, phase := range phases {
+		if phase != RetryPhaseProgress {
+			t.Fatalf("got phase %s, want %s", phase, RetryPhaseProgress)
+		}
+	}
+}
+
+func TestRetryRangeMismatch(t *testing.T) {
+	ctx := context.Background()
+
+	mismatch := testutil.Respond(http.StatusPartialContent, "Content-Range", "bytes 8-11/12")
+	mismatch.Body = []byte("rld!")
+	transport := testutil.NewReplayTransport([]byte("Hello World!"), mismatch)
+
+	var infos []RetryInfo
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req,
+		WithRetry(1, time.Millisecond),
+		WithRetryHandler(func(info RetryInfo) error {
+			infos = append(infos, info)
+			return nil
+		}),
+	)
+	defer rsc.Close()
+
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+	if len(infos) != 1 || infos[0].Reason != RetryRangeMismatch || !errors.Is(infos[0].Err, ErrRangeOffsetMismatch) {
+		t.Fatalf("got retries %+v, want one for %v", infos, ErrRangeOffsetMismatch)
+	}
+	for _, r := range transport.Requests() {
+		if r.Range != "bytes=6-" {
+			t.Fatalf("got range %q, want %q", r.Range, "bytes=6-")
+		}
+	}
+}
+
+func TestRetryServerError(t *testing.T) {
+	ctx := context.Background()
+
+	transport := testutil.NewReplayTransport([]byte("Hello World!"),
+		testutil.Respond(http.StatusBadGateway),
+		testutil.Respond(http.StatusServiceUnavailable),
+	)
+
+	var infos []RetryInfo
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req,
+		WithRetry(0, 0),
+		WithServerErrorRetry(2, time.Millisecond),
+		WithRetryHandler(func(info RetryInfo) error {
+			infos = append(infos, info)
+			return nil
+		}),
+	)
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if len(infos) != 2 {
+		t.Fatalf("got %d retries, want %d", len(infos), 2)
+	}
+	for i, info := range infos {
+		if info.Reason != RetryServerError || info.Delay != time.Millisecond<<i {
+			t.Fatalf("got retry %d reason %s delay %s, want %s delay %s", i+1, info.Reason, info.Delay, RetryServerError, time.Millisecond<<i)
+		}
+	}
+}
+
+func TestRetryServerErrorExhausted(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		status  int
+		retries int
+		want    int
+	}{
+		{http.StatusServiceUnavailable, 1, 2},
+		{http.StatusNotFound, 1, 1},
+	}
+	for _, tt := range tests {
+		transport := testutil.NewReplayTransport([]byte("Hello World!"),
+			testutil.Respond(tt.status),
+			testutil.Respond(tt.status),
+			testutil.Respond(tt.status),
+		)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, transport, req, WithServerErrorRetry(tt.retries, time.Millisecond))
+
+		_, err = io.ReadAll(rsc)
+		if tt.status == http.StatusServiceUnavailable {
+			var statusErr *StatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status || !errors.Is(err, ErrRetriesExhausted) {
+				t.Fatalf("got %v, want exhausted status error %d", err, tt.status)
+			}
+		}
+		if requests := transport.Requests(); len(requests) != tt.want {
+			t.Fatalf("status %d: got %d requests, want %d", tt.status, len(requests), tt.want)
+		}
+		rsc.Close()
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	ctx := context.Background()
+
+	date := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	transport := testutil.NewReplayTransport([]byte("Hello World!"),
+		testutil.Respond(http.StatusTooManyRequests, "Retry-After", "2"),
+		testutil.Respond(http.StatusServiceUnavailable, "Retry-After", date),
+		testutil.Respond(http.StatusTooManyRequests),
+	)
+
+	var infos []RetryInfo
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req,
+		WithRetry(2, time.Millisecond),
+		WithServerErrorRetry(1, time.Millisecond),
+		WithMaxRetryAfter(5*time.Millisecond),
+		WithRetryHandler(func(info RetryInfo) error {
+			infos = append(infos, info)
+			return nil
+		}),
+	)
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if len(infos) != 3 {
+		t.Fatalf("got %d retries, want %d", len(infos), 3)
+	}
+	if infos[0].Reason != RetryTooManyRequests || infos[0].RetryAfter != 2*time.Second || infos[0].Delay != 5*time.Millisecond {
+		t.Errorf("got first retry %+v, want too many requests after 2s capped at 5ms", infos[0])
+	}
+	if infos[1].Reason != RetryServerError || infos[1].RetryAfter < 59*time.Minute || infos[1].Delay != 5*time.Millisecond {
+		t.Errorf("got second retry %+v, want server error after an hour capped at 5ms", infos[1])
+	}
+	if infos[2].RetryAfter != 0 || infos[2].Delay != 2*time.Millisecond {
+		t.Errorf("got third retry %+v, want the regular backoff of 2ms", infos[2])
+	}
+}
+
+func TestStatusErrorRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{"soon", 0},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		err := &StatusError{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+		if tt.value != "" {
+			err.Header.Set("Retry-After", tt.value)
+		}
+		if got := err.RetryAfter(); got != tt.want {
+			t.Errorf("RetryAfter() with %q = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+// failOnceTransport fails the first request with err and sends the others with base.
+type failOnceTransport struct {
+	base   http.RoundTripper
+	err    error
+	failed bool
+}
+
+func (t *failOnceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.failed {
+		t.failed = true
+		return nil, t.err
+	}
+	return t.base.RoundTrip(req)
+}
+
+func TestRetryTransport(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	refused := errors.New("connection refused")
+	transport := &failOnceTransport{base: s.Client().Transport, err: refused}
+
+	var infos []RetryInfo
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req,
+		WithRetry(1, time.Millisecond),
+		WithRetryHandler(func(info RetryInfo) error {
+			infos = append(infos, info)
+			return nil
+		}),
+	)
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if len(infos) != 1 || infos[0].Reason != RetryTransport || infos[0].Err != refused {
+		t.Fatalf("got retries %+v, want one for %v", infos, refused)
+	}
+}
@@ -0,0 +1,102 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// SectionSeeker reads the section [off, off+n) of a remote content, with offsets relative to the section.
+type SectionSeeker struct {
+	s   *Seeker
+	off int64
+	n   int64
+}
+
+// NewSectionSeeker returns a SectionSeeker for the n bytes at off of the content of req, configured by opts.
+// off must not be negative.
+// Every range it requests ends at the end of the section, so the server never sends bytes past it.
+func NewSectionSeeker(ctx context.Context, transport http.RoundTripper, req *http.Request, off, n int64, opts ...Option) *SectionSeeker {
+	opts = append(opts[:len(opts):len(opts)], func(c *config) {
+		c.sectionEnd = off + n
+	})
+	s := NewSeekerWithOptions(ctx, transport, req, opts...)
+	s.offset = off
+	return &SectionSeeker{s: s, off: off, n: n}
+}
+
+// streamLength returns the length of the range requested for the stream at offset, or -1 for the rest of the content.
+func (s *Seeker) streamLength(offset int64) int64 {
+	if s.cfg.sectionEnd == 0 {
+		return -1
+	}
+	return max(s.cfg.sectionEnd-offset, 0)
+}
+
+// Read reads up to the end of the section, where it returns io.EOF.
+// It returns io.EOF earlier if the content ends inside the section.
+func (r *SectionSeeker) Read(p []byte) (int, error) {
+	rest := r.off + r.n - r.s.Offset()
+	if rest <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	return r.s.Read(p)
+}
+
+// Seek sets the offset in the section for the next Read. As with Seeker, seeking past the end is allowed.
+func (r *SectionSeeker) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.s.Offset() - r.off
+	case io.SeekEnd:
+		offset += r.n
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if _, err := r.s.Seek(r.off+offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return offset, nil
+}
+
+// ReadAt reads len(p) bytes at off of the section with its own range request, as Seeker.ReadAt does.
+func (r *SectionSeeker) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= r.n {
+		return 0, io.EOF
+	}
+	if rest := r.n - off; int64(len(p)) > rest {
+		n, err := r.s.ReadAt(p[:rest], r.off+off)
+		if err == nil {
+			err = io.EOF
+		}
+		return n, err
+	}
+	return r.s.ReadAt(p, r.off+off)
+}
+
+// Size returns the size of the section.
+func (r *SectionSeeker) Size() int64 {
+	return r.n
+}
+
+// Seeker returns the Seeker reading the section, such as for its Stats.
+// Its offsets are those of the whole content.
+func (r *SectionSeeker) Seeker() *Seeker {
+	return r.s
+}
+
+// Close closes the Seeker reading the section.
+func (r *SectionSeeker) Close() error {
+	return r.s.Close()
+}
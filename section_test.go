@@ -0,0 +1,103 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSectionSeeker(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+
+	var mut sync.Mutex
+	var ranges []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		ranges = append(ranges, r.Header.Get("Range"))
+		mut.Unlock()
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewSectionSeeker(ctx, s.Client().Transport, req, 3, 6)
+	defer r.Close()
+
+	if r.Size() != 6 {
+		t.Fatalf("got size %d, want 6", r.Size())
+	}
+	got, err := io.ReadAll(r)
+	if err != nil || string(got) != "lo Wor" {
+		t.Fatalf("ReadAll() = %q, %v, want %q", got, err, "lo Wor")
+	}
+
+	if off, err := r.Seek(0, io.SeekEnd); err != nil || off != 6 {
+		t.Fatalf("Seek(0, io.SeekEnd) = %d, %v, want 6", off, err)
+	}
+	if n, err := r.Read(make([]byte, 4)); n != 0 || err != io.EOF {
+		t.Fatalf("Read() at the end = %d, %v, want 0, io.EOF", n, err)
+	}
+
+	// A read straddling the end of the section stops at it.
+	if off, err := r.Seek(-2, io.SeekCurrent); err != nil || off != 4 {
+		t.Fatalf("Seek(-2, io.SeekCurrent) = %d, %v, want 4", off, err)
+	}
+	buf := make([]byte, 10)
+	n, err := io.ReadFull(r, buf)
+	if err != io.ErrUnexpectedEOF || string(buf[:n]) != "or" {
+		t.Fatalf("ReadFull() at 4 = %q, %v, want %q", buf[:n], err, "or")
+	}
+	if n, err := r.ReadAt(buf, 1); err != io.EOF || string(buf[:n]) != "o Wor" {
+		t.Fatalf("ReadAt(1) = %q, %v, want %q, io.EOF", buf[:n], err, "o Wor")
+	}
+
+	// Seeks outside the section.
+	if _, err := r.Seek(-1, io.SeekStart); err == nil {
+		t.Fatal("Seek(-1, io.SeekStart) succeeded, want an error")
+	}
+	if off, err := r.Seek(10, io.SeekStart); err != nil || off != 10 {
+		t.Fatalf("Seek(10, io.SeekStart) = %d, %v, want 10", off, err)
+	}
+	if n, err := r.Read(buf); n != 0 || err != io.EOF {
+		t.Fatalf("Read() past the end = %d, %v, want 0, io.EOF", n, err)
+	}
+
+	mut.Lock()
+	defer mut.Unlock()
+	for _, rng := range ranges {
+		if !strings.HasPrefix(rng, "bytes=") || !strings.HasSuffix(rng, "-8") {
+			t.Errorf("got Range %q, want one ending at the end of the section", rng)
+		}
+	}
+}
+
+func TestSectionSeekerPastContent(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewSectionSeeker(ctx, s.Client().Transport, req, 8, 10)
+	defer r.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil || string(got) != "rld!" {
+		t.Fatalf("ReadAll() = %q, %v, want %q", got, err, "rld!")
+	}
+}
@@ -0,0 +1,60 @@
+package httpseek
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Signer signs an outgoing request, such as with AWS Signature Version 4 or a GCS HMAC signature.
+// Sign may set headers and the URL of req.
+type Signer interface {
+	Sign(req *http.Request) error
+}
+
+// SignError is returned when the Signer fails to sign a request.
+type SignError struct {
+	Err error
+}
+
+func (e *SignError) Error() string {
+	return fmt.Sprintf("failed to sign request: %v", e.Err)
+}
+
+func (e *SignError) Unwrap() error {
+	return e.Err
+}
+
+// PrepareError is returned when the preparer set by WithRequestPreparer fails to prepare a request.
+type PrepareError struct {
+	Err error
+}
+
+func (e *PrepareError) Error() string {
+	return fmt.Sprintf("failed to prepare request: %v", e.Err)
+}
+
+func (e *PrepareError) Unwrap() error {
+	return e.Err
+}
+
+// prepare calls the configured preparer, if any, on req.
+func (c *config) prepare(req *http.Request) error {
+	if c.preparer == nil {
+		return nil
+	}
+	if err := c.preparer(req.Context(), req); err != nil {
+		return &PrepareError{Err: err}
+	}
+	return nil
+}
+
+// sign signs req with the configured Signer, if any.
+func (c *config) sign(req *http.Request) error {
+	if c.signer == nil {
+		return nil
+	}
+	if err := c.signer.Sign(req); err != nil {
+		return &SignError{Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+// recordingSigner signs over the method, path, Range and If-Range of a request and records what it signed.
+type recordingSigner struct {
+	mut    sync.Mutex
+	signed []string
+}
+
+func signature(req *http.Request) string {
+	return req.Method + " " + req.URL.Path + "|" + req.Header.Get("Range") + "|" + req.Header.Get("If-Range")
+}
+
+func (s *recordingSigner) Sign(req *http.Request) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	sig := signature(req)
+	s.signed = append(s.signed, sig)
+	req.Header.Set("X-Signature", sig)
+	return nil
+}
+
+func TestSigner(t *testing.T) {
+	ctx := context.Background()
+
+	var mut sync.Mutex
+	var received []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sig := signature(r)
+		if r.Header.Get("X-Signature") != sig {
+			http.Error(w, "bad signature", http.StatusForbidden)
+			return
+		}
+		mut.Lock()
+		received = append(received, sig)
+		mut.Unlock()
+		if r.URL.Path != "/target" {
+			http.Redirect(w, r, "/target", http.StatusFound)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/source", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := &recordingSigner{}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithSigner(signer), WithStatusAction(http.StatusForbidden, StatusFail))
+	defer rsc.Close()
+
+	if _, err := rsc.Seek(-6, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+	if _, err := rsc.Seek(2, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(rsc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"HEAD /source||",
+		"HEAD /target||",
+		"GET /source|bytes=6-|",
+		"GET /target|bytes=6-|",
+		`GET /source|bytes=2-|"v1"`,
+		`GET /target|bytes=2-|"v1"`,
+	}
+	mut.Lock()
+	defer mut.Unlock()
+	signer.mut.Lock()
+	defer signer.mut.Unlock()
+	if len(signer.signed) != len(want) || len(received) != len(want) {
+		t.Fatalf("signed %q and received %q, want %q", signer.signed, received, want)
+	}
+	for i := range want {
+		if signer.signed[i] != want[i] || received[i] != want[i] {
+			t.Fatalf("signed %q and received %q, want %q", signer.signed, received, want)
+		}
+	}
+}
+
+type failingSigner struct{}
+
+func (failingSigner) Sign(req *http.Request) error {
+	return errors.New("no credentials")
+}
+
+func TestSignerError(t *testing.T) {
+	ctx := context.Background()
+
+	var requests int
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithSigner(failingSigner{}))
+	defer rsc.Close()
+
+	_, err = rsc.Read(make([]byte, 1))
+	var signErr *SignError
+	if !errors.As(err, &signErr) {
+		t.Fatalf("got %v, want a SignError", err)
+	}
+	if requests != 0 || rsc.Stats().Retries != 0 {
+		t.Fatalf("got %d requests and %d retries, want none", requests, rsc.Stats().Retries)
+	}
+}
+
+func TestRequestPreparer(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	expired := errors.New("token refresh failed")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/source", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer stale")
+	transport := testutil.NewReplayTransport(content,
+		testutil.Respond(http.StatusFound, "Location", "/target"),
+		testutil.ServeThenFail(5, errors.New("connection reset")),
+	)
+	var tokens int
+	var reasons []RetryReason
+	rsc := NewSeekerWithOptions(ctx, transport, req,
+		WithRetry(3, time.Millisecond),
+		WithRetryHandler(func(info RetryInfo) error {
+			reasons = append(reasons, info.Reason)
+			return nil
+		}),
+		WithRequestPreparer(func(ctx context.Context, req *http.Request) error {
+			tokens++
+			if tokens == 3 {
+				return expired
+			}
+			req.Header.Set("Authorization", fmt.Sprintf("Bearer token-%d", tokens))
+			return nil
+		}),
+	)
+	defer rsc.Close()
+
+	data, err := io.ReadAll(NewMustReader(rsc, nil))
+	if err != nil || string(data) != string(content) {
+		t.Fatalf("got %q, %v, want %q", data, err, content)
+	}
+
+	var got []string
+	for _, r := range transport.Requests() {
+		got = append(got, r.URL+" "+r.Header.Get("Authorization"))
+	}
+	want := []string{
+		"http://example.com/source Bearer token-1",
+		"http://example.com/target Bearer token-2",
+		// The resume after the connection reset, retried after token-3 failed.
+		"http://example.com/source Bearer token-4",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got requests %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(reasons, []RetryReason{RetryPrepare}) {
+		t.Errorf("got retries %v, want one for %v", reasons, RetryPrepare)
+	}
+}
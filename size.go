@@ -0,0 +1,177 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+var (
+	// ErrSizeChanged is matched by errors.Is when a response reports a total size different from the one learned earlier.
+	ErrSizeChanged = errors.New("content size changed")
+
+	// ErrSizeShrunk is matched by errors.Is when the reported total size is smaller than the one learned earlier.
+	ErrSizeShrunk = errors.New("content size shrunk")
+)
+
+// SizeChangedError is returned when a response reports a total size different from the one learned earlier.
+type SizeChangedError struct {
+	// Old is the size learned earlier.
+	Old int64
+	// New is the size reported by the response.
+	New int64
+}
+
+func (e *SizeChangedError) Error() string {
+	return fmt.Sprintf("content size changed from %d to %d", e.Old, e.New)
+}
+
+func (e *SizeChangedError) Is(target error) bool {
+	return target == ErrSizeChanged || (target == ErrSizeShrunk && e.New < e.Old)
+}
+
+// SizeChangePolicy selects what happens when a response reports a different total size.
+type SizeChangePolicy int
+
+const (
+	// SizeChangeFail fails the request with a SizeChangedError.
+	SizeChangeFail SizeChangePolicy = iota
+	// SizeChangeAcceptGrowth accepts a larger size, as expected for append-only resources,
+	// and fails if the size shrinks.
+	SizeChangeAcceptGrowth
+	// SizeChangeAcceptAny accepts any new size.
+	SizeChangeAcceptAny
+)
+
+// checkSize compares the size reported by a response with the size learned earlier.
+func (s *Seeker) checkSize(size int64) error {
+	if s.size < 0 || size < 0 || size == s.size {
+		return nil
+	}
+	switch s.cfg.sizeChangePolicy {
+	case SizeChangeAcceptAny:
+		return nil
+	case SizeChangeAcceptGrowth:
+		if size > s.size {
+			return nil
+		}
+	}
+	return &SizeChangedError{Old: s.size, New: size}
+}
+
+// setSize updates the known size and notifies about newly learned sizes.
+// It must be called with s.mu held.
+func (s *Seeker) setSize(size int64) {
+	old := s.size
+	s.size = size
+	if size >= 0 && size != old {
+		s.emitLocked(SizeDiscovered{Size: size, Old: old})
+	}
+}
+
+// discoverSize learns the content length without opening the stream, so that Seek with
+// io.SeekEnd works before anything was read. It asks with a HEAD request first and falls
+// back to a one-byte range probe when the server rejects HEAD or omits Content-Length.
+func (s *Seeker) discoverSize(ctx context.Context) error {
+	resp, _, err := s.head(ctx)
+	if err == nil {
+		_ = resp.Body.Close()
+		if resp.StatusCode == http.StatusOK && resp.ContentLength >= 0 {
+			s.mu.Lock()
+			s.recordAcceptRanges(resp.Header)
+			s.setSize(resp.ContentLength)
+			s.mu.Unlock()
+			return nil
+		}
+	}
+
+	_, err = s.fetch(ctx, make([]byte, 1), 0)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	if s.Size() < 0 {
+		return errors.New("content length not known")
+	}
+	return nil
+}
+
+// head sends a HEAD request for the content and returns the response and the request
+// that produced it after redirects.
+func (s *Seeker) head(ctx context.Context) (*http.Response, *http.Request, error) {
+	req := s.req.Clone(ctx)
+	req.Method = http.MethodHead
+	req.Body = nil
+	req.GetBody = nil
+	req.ContentLength = 0
+	s.mu.Lock()
+	s.pinHeaders(req)
+	s.stats.Requests++
+	s.mu.Unlock()
+	resp, req, _, err := s.roundTrip(req)
+	return resp, req, err
+}
+
+// Probe learns the size and the headers of the content with a request for its first byte,
+// for servers that block HEAD or answer it differently from GET, such as presigned URLs.
+// The response, with its tiny body closed, is kept for Response if no response was received before.
+// A server that answers with the full content reports its size in Content-Length; the body is closed unread.
+// Probe does not disturb the stream read by Read.
+func (s *Seeker) Probe(ctx context.Context) error {
+	defer s.flushEvents()
+	r, size, resp, err := s.open(ctx, 0, 1)
+	if err != nil {
+		return err
+	}
+	_ = r.Close()
+	if resp != nil && resp.StatusCode != http.StatusOK {
+		return &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if size >= 0 {
+		s.setSize(size)
+	}
+	if last := s.lastResponse; s.firstResponse == nil && last != nil &&
+		(last.StatusCode == http.StatusOK || last.StatusCode == http.StatusPartialContent) {
+		s.firstResponse = last
+	}
+	return nil
+}
+
+// preflight learns what a HEAD response tells about the content, as enabled by WithPreflight.
+func (s *Seeker) preflight() {
+	defer s.flushEvents()
+	s.learnFromHead(s.ctx)
+}
+
+// learnFromHead records the size, the validators and the headers reported by a HEAD response,
+// keeping the response for Response if none was received before.
+// It reports false if the HEAD request failed, was rejected or disagrees with what is known.
+func (s *Seeker) learnFromHead(ctx context.Context) bool {
+	resp, req, err := s.head(ctx)
+	if err != nil {
+		return false
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if resp.ContentLength >= 0 && s.checkSize(resp.ContentLength) != nil {
+		return false
+	}
+	s.recordAcceptRanges(resp.Header)
+	if s.checkResponse(0, req, resp) != nil {
+		return false
+	}
+	if resp.ContentLength >= 0 {
+		s.setSize(resp.ContentLength)
+	}
+	if s.firstResponse == nil {
+		s.firstResponse = resp
+	}
+	return true
+}
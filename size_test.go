@@ -0,0 +1,351 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSizeChangePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	var content []byte
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	tests := []struct {
+		policy  SizeChangePolicy
+		next    string
+		want    string
+		wantErr error
+	}{
+		{SizeChangeFail, "Hello World! More", "", ErrSizeChanged},
+		{SizeChangeAcceptGrowth, "Hello World! More", "World! More", nil},
+		{SizeChangeAcceptGrowth, "Hello Wor", "", ErrSizeShrunk},
+		{SizeChangeAcceptAny, "Hello Wor", "Wor", nil},
+	}
+	for _, tt := range tests {
+		content = []byte("Hello World!")
+
+		var sizes []int64
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req,
+			WithSizeChangePolicy(tt.policy),
+			WithOnSizeKnown(func(size int64) {
+				sizes = append(sizes, size)
+			}),
+		)
+
+		if _, err := io.ReadAll(rsc); err != nil {
+			t.Fatal(err)
+		}
+
+		content = []byte(tt.next)
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(rsc)
+		if tt.wantErr != nil {
+			var sizeErr *SizeChangedError
+			if !errors.Is(err, tt.wantErr) || !errors.As(err, &sizeErr) || sizeErr.New != int64(len(tt.next)) {
+				t.Fatalf("policy %d: got %v, want %v", tt.policy, err, tt.wantErr)
+			}
+			var changedErr *ContentChangedError
+			if !errors.Is(err, ErrContentChanged) || !errors.As(err, &changedErr) ||
+				changedErr.Offset != 6 || changedErr.Before.Size != 12 || changedErr.After.Size != int64(len(tt.next)) {
+				t.Fatalf("policy %d: got %v, want a content change from size 12 to %d", tt.policy, err, len(tt.next))
+			}
+			rsc.Close()
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Fatalf("policy %d: got %q, want %q", tt.policy, got, tt.want)
+		}
+		if want := []int64{12, int64(len(tt.next))}; !reflect.DeepEqual(sizes, want) {
+			t.Fatalf("policy %d: got sizes %v, want %v", tt.policy, sizes, want)
+		}
+		rsc.Close()
+	}
+}
+
+func TestSeekEndDiscoversSize(t *testing.T) {
+	ctx := context.Background()
+
+	for _, rejectHead := range []bool{false, true} {
+		t.Run(fmt.Sprintf("reject head %v", rejectHead), func(t *testing.T) {
+			var heads int
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodHead {
+					heads++
+					if rejectHead {
+						w.WriteHeader(http.StatusMethodNotAllowed)
+						return
+					}
+				}
+				http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+			}))
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeeker(ctx, s.Client().Transport, req)
+			defer rsc.Close()
+
+			off, err := rsc.Seek(-4, io.SeekEnd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if off != 8 {
+				t.Fatalf("got offset %d, want 8", off)
+			}
+			got, err := io.ReadAll(rsc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "rld!" {
+				t.Fatalf("got %q, want %q", got, "rld!")
+			}
+			if heads != 1 {
+				t.Fatalf("got %d HEAD requests, want 1", heads)
+			}
+		})
+	}
+}
+
+func TestExpectedSize(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	for _, tt := range []struct {
+		size    int64
+		wantErr bool
+	}{
+		{size: 12},
+		{size: 15, wantErr: true},
+	} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithExpectedSize(tt.size))
+
+		if _, err := rsc.Seek(-6, io.SeekEnd); err != nil {
+			t.Fatal(err)
+		}
+		if rsc.Stats().Requests != 0 {
+			t.Fatalf("size %d: got %d requests for SeekEnd, want none", tt.size, rsc.Stats().Requests)
+		}
+		got, err := io.ReadAll(rsc)
+		if tt.wantErr {
+			if !errors.Is(err, ErrSizeChanged) {
+				t.Fatalf("size %d: got %v, want %v", tt.size, err, ErrSizeChanged)
+			}
+		} else if err != nil || string(got) != "World!" {
+			t.Fatalf("size %d: got %q, %v", tt.size, got, err)
+		}
+		rsc.Close()
+	}
+}
+
+func TestExpectedSizeChunked(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Flushing before the body is written sends it chunked, without a Content-Length.
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		io.WriteString(w, "Hello World!")
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithExpectedSize(12))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 5)); err != nil {
+		t.Fatal(err)
+	}
+	if size := rsc.Size(); size != 12 {
+		t.Fatalf("got size %d after a chunked response, want 12", size)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil || string(got) != " World!" {
+		t.Fatalf("got %q, %v", got, err)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	ctx := context.Background()
+
+	for _, ignoreRange := range []bool{false, true} {
+		t.Run(fmt.Sprintf("ignore range %v", ignoreRange), func(t *testing.T) {
+			var ranges []string
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ranges = append(ranges, r.Header.Get("Range"))
+				w.Header().Set("X-Probe", "yes")
+				if ignoreRange {
+					r.Header.Del("Range")
+				}
+				http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+			}))
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeeker(ctx, s.Client().Transport, req)
+			defer rsc.Close()
+
+			if _, err := io.ReadFull(rsc, make([]byte, 2)); err != nil {
+				t.Fatal(err)
+			}
+			if err := rsc.Probe(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if size := rsc.Size(); size != 12 {
+				t.Fatalf("got size %d, want 12", size)
+			}
+			resp, err := rsc.Response()
+			if err != nil || resp.Header.Get("X-Probe") != "yes" {
+				t.Fatalf("got response %v, %v, want the probed headers", resp, err)
+			}
+			got, err := io.ReadAll(rsc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "llo World!" {
+				t.Fatalf("got %q after the probe, want %q", got, "llo World!")
+			}
+			if want := []string{"", "bytes=0-0"}; !reflect.DeepEqual(ranges, want) {
+				t.Fatalf("got ranges %q, want %q", ranges, want)
+			}
+		})
+	}
+}
+
+func TestProbeOnly(t *testing.T) {
+	ctx := context.Background()
+
+	var served int64
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		cw := &countingWriter{ResponseWriter: w}
+		http.ServeContent(cw, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+		served += cw.n
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if err := rsc.Probe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if size := rsc.Size(); size != 12 {
+		t.Fatalf("got size %d, want 12", size)
+	}
+	resp, err := rsc.Response()
+	if err != nil || resp.StatusCode != http.StatusPartialContent {
+		t.Fatalf("got response %v, %v, want the 206 of the probe", resp, err)
+	}
+	if served != 1 {
+		t.Fatalf("served %d bytes, want 1", served)
+	}
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+func TestPreflight(t *testing.T) {
+	ctx := context.Background()
+
+	for _, status := range []int{http.StatusOK, http.StatusMethodNotAllowed} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			var methods []string
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				methods = append(methods, r.Method)
+				if r.Method == http.MethodHead && status != http.StatusOK {
+					w.WriteHeader(status)
+					return
+				}
+				w.Header().Set("ETag", `"v1"`)
+				http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+			}))
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithPreflight())
+			defer rsc.Close()
+
+			wantSize, wantETag := int64(12), `"v1"`
+			if status != http.StatusOK {
+				wantSize, wantETag = -1, ""
+			}
+			if size := rsc.Size(); size != wantSize {
+				t.Fatalf("got size %d after construction, want %d", size, wantSize)
+			}
+			if etag := rsc.Validator().ETag; etag != wantETag {
+				t.Fatalf("got ETag %q after construction, want %q", etag, wantETag)
+			}
+			if want := []string{http.MethodHead}; !reflect.DeepEqual(methods, want) {
+				t.Fatalf("got requests %q after construction, want %q", methods, want)
+			}
+
+			resp, err := rsc.Response()
+			if err != nil || resp.Header.Get("ETag") != `"v1"` {
+				t.Fatalf("got response %v, %v, want one with the ETag", resp, err)
+			}
+			got, err := io.ReadAll(rsc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != "Hello World!" {
+				t.Fatalf("got %q, want %q", got, "Hello World!")
+			}
+		})
+	}
+}
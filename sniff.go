@@ -0,0 +1,33 @@
+package httpseek
+
+import (
+	"io"
+	"net/http"
+)
+
+const sniffLen = 512
+
+// DetectContentType returns the content type detected by http.DetectContentType from the first 512 bytes of the content.
+// The server's Content-Type header is only used when the bytes do not identify a type.
+// It issues a bounded range request for the sniffed bytes, so the read position is unchanged,
+// and caches the result.
+func (s *Seeker) DetectContentType() (string, error) {
+	if s.sniffedContentType != "" {
+		return s.sniffedContentType, nil
+	}
+
+	buf := make([]byte, sniffLen)
+	n, err := s.fetch(s.ctx, buf, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	contentType := http.DetectContentType(buf[:n])
+	s.mu.Lock()
+	if (n == 0 || contentType == "application/octet-stream") && s.contentType != "" {
+		contentType = s.contentType
+	}
+	s.mu.Unlock()
+	s.sniffedContentType = contentType
+	return contentType, nil
+}
@@ -0,0 +1,85 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDetectContentType(t *testing.T) {
+	ctx := context.Background()
+
+	content := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 1024)...)
+	var ranges []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("Content-Type", "text/plain")
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	head := make([]byte, 3)
+	if _, err := io.ReadFull(rsc, head); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := rsc.DetectContentType()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "image/png" {
+			t.Fatalf("got %q, want %q", got, "image/png")
+		}
+	}
+
+	rest, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(append(head, rest...), content) {
+		t.Fatal("the read position was disturbed by sniffing")
+	}
+	if len(ranges) != 2 || ranges[1] != "bytes=0-511" {
+		t.Fatalf("got ranges %q, want a single bounded sniff request", ranges)
+	}
+}
+
+func TestDetectContentTypeFallback(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-custom")
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte{0, 1, 2, 3}))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	got, err := rsc.DetectContentType()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "application/x-custom" {
+		t.Fatalf("got %q, want %q", got, "application/x-custom")
+	}
+	if rsc.Size() != 4 {
+		t.Fatalf("got size %d, want %d", rsc.Size(), 4)
+	}
+}
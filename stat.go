@@ -0,0 +1,79 @@
+package httpseek
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"time"
+)
+
+// fileInfo describes the content as a read-only file.
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (fi *fileInfo) Name() string       { return fi.name }
+func (fi *fileInfo) Size() int64        { return fi.size }
+func (fi *fileInfo) Mode() fs.FileMode  { return 0o444 }
+func (fi *fileInfo) ModTime() time.Time { return fi.modTime }
+func (fi *fileInfo) IsDir() bool        { return false }
+func (fi *fileInfo) Sys() any           { return nil }
+
+// Stat describes the content as a read-only file: the name is the filename of the Content-Disposition,
+// or the last element of the URL path, the size is the total size, or -1 if it is unknown,
+// and the modification time is the Last-Modified, or the zero time if there is none.
+// If no response was received yet, Stat learns them with a HEAD request, or a one-byte range request
+// if HEAD fails; later calls describe what is known without sending requests.
+func (s *Seeker) Stat() (fs.FileInfo, error) {
+	s.mu.Lock()
+	known := s.firstResponse != nil || s.lastResponse != nil
+	s.mu.Unlock()
+	if !known && !s.learnFromHead(s.ctx) {
+		if err := s.Probe(s.ctx); err != nil {
+			return nil, err
+		}
+	}
+	s.flushEvents()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	resp := s.firstResponse
+	if resp == nil {
+		resp = s.lastResponse
+	}
+	info := &fileInfo{
+		name: s.fileName(resp),
+		size: s.size,
+	}
+	if t, err := http.ParseTime(s.lastModified); err == nil {
+		info.modTime = t
+	}
+	return info, nil
+}
+
+// fileName returns the filename given by the Content-Disposition of resp,
+// or the last element of the URL path, or the host if the path has none.
+func (s *Seeker) fileName(resp *http.Response) string {
+	if resp != nil {
+		if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+			if name := path.Base(params["filename"]); params["filename"] != "" && name != "/" && name != "." {
+				return name
+			}
+		}
+	}
+	name := path.Base(s.req.URL.Path)
+	if name == "/" || name == "." {
+		return s.req.URL.Host
+	}
+	return name
+}
+
+var _ fs.File = (*Seeker)(nil)
+
+// closedError is the error of op on a closed Seeker, wrapping fs.ErrClosed like os.File does.
+func (s *Seeker) closedError(op string) error {
+	return &fs.PathError{Op: op, Path: s.req.URL.Redacted(), Err: fs.ErrClosed}
+}
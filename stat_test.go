@@ -0,0 +1,137 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStat(t *testing.T) {
+	ctx := context.Background()
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		path        string
+		disposition string
+		rejectHead  bool
+		chunked     bool
+		wantName    string
+		wantSize    int64
+		wantMod     time.Time
+		wantMethods []string
+	}{
+		{"head", "/dir/file.bin", "", false, false, "file.bin", 12, modified, []string{http.MethodHead}},
+		{"disposition", "/download", `attachment; filename="report.pdf"`, false, false, "report.pdf", 12, modified, []string{http.MethodHead}},
+		{"head rejected", "/dir/file.bin", "", true, false, "file.bin", 12, modified, []string{http.MethodHead, http.MethodGet}},
+		{"unknown size", "/", "", true, true, "", -1, time.Time{}, []string{http.MethodHead, http.MethodGet}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var methods []string
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				methods = append(methods, r.Method)
+				if tt.rejectHead && r.Method == http.MethodHead {
+					w.WriteHeader(http.StatusMethodNotAllowed)
+					return
+				}
+				if tt.disposition != "" {
+					w.Header().Set("Content-Disposition", tt.disposition)
+				}
+				if tt.chunked {
+					w.Write([]byte("Hello "))
+					w.(http.Flusher).Flush()
+					w.Write([]byte("World!"))
+					return
+				}
+				http.ServeContent(w, r, "test", modified, bytes.NewReader([]byte("Hello World!")))
+			}))
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeeker(ctx, s.Client().Transport, req)
+			defer rsc.Close()
+
+			for i := 0; i < 2; i++ {
+				info, err := rsc.Stat()
+				if err != nil {
+					t.Fatal(err)
+				}
+				wantName := tt.wantName
+				if wantName == "" {
+					wantName = req.URL.Host
+				}
+				if info.Name() != wantName || info.Size() != tt.wantSize || !info.ModTime().Equal(tt.wantMod) {
+					t.Fatalf("got %q size %d modified %s, want %q size %d modified %s",
+						info.Name(), info.Size(), info.ModTime(), wantName, tt.wantSize, tt.wantMod)
+				}
+				if info.Mode() != fs.FileMode(0o444) || info.IsDir() {
+					t.Fatalf("got mode %s, want a read-only file", info.Mode())
+				}
+			}
+			if !reflect.DeepEqual(methods, tt.wantMethods) {
+				t.Fatalf("got requests %q, want %q", methods, tt.wantMethods)
+			}
+		})
+	}
+}
+
+func TestSeekerFile(t *testing.T) {
+	ctx := context.Background()
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/hello.txt", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var f fs.File = NewSeeker(ctx, s.Client().Transport, req)
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "hello.txt" || info.Size() != 12 {
+		t.Fatalf("got %q size %d before any read, want %q size 12", info.Name(), info.Size(), "hello.txt")
+	}
+
+	seeker, ok := f.(io.Seeker)
+	if !ok {
+		t.Fatal("fs.File is not an io.Seeker")
+	}
+	if _, err := seeker.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Read(make([]byte, 1)); !errors.Is(err, fs.ErrClosed) {
+		t.Fatalf("got %v from Read after Close, want %v", err, fs.ErrClosed)
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); !errors.Is(err, fs.ErrClosed) {
+		t.Fatalf("got %v from Seek after Close, want %v", err, fs.ErrClosed)
+	}
+	if _, err := f.(io.ReaderAt).ReadAt(make([]byte, 1), 0); !errors.Is(err, fs.ErrClosed) {
+		t.Fatalf("got %v from ReadAt after Close, want %v", err, fs.ErrClosed)
+	}
+}
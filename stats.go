@@ -0,0 +1,73 @@
+package httpseek
+
+// Stats reports counters collected by a Seeker.
+type Stats struct {
+	// Requests is the number of HTTP requests issued.
+	Requests int
+	// BytesRead is the number of bytes of content delivered to the caller.
+	BytesRead int64
+	// AvoidedRequests is the number of seeks satisfied by the already-open stream.
+	AvoidedRequests int
+	// HedgedRequests is the number of extra requests sent because a request was slow to respond.
+	HedgedRequests int
+	// HedgeWins is the number of times an extra hedged request responded first.
+	HedgeWins int
+	// Retries is the number of failed requests that were retried.
+	Retries int
+	// WastedBytes is the number of bytes received and discarded because they preceded the requested offset
+	// or were skipped by a forward seek.
+	WastedBytes int64
+	// Releases is the number of times an open body was closed voluntarily by Release.
+	Releases int
+	// SlowAborts is the number of attempts aborted because their throughput dropped below the configured minimum.
+	SlowAborts int
+	// AuditDropped is the number of audit records dropped because the audit writer could not keep up.
+	AuditDropped int64
+	// OverlapBytes is the number of bytes re-requested before resumed offsets to verify the resumed content.
+	OverlapBytes int64
+	// PrefetchedBytes is the number of bytes received by the tail prefetch.
+	PrefetchedBytes int64
+	// PrefetchUsedBytes is the number of distinct prefetched bytes served by Read;
+	// the rest of PrefetchedBytes was wasted.
+	PrefetchUsedBytes int64
+	// Restarts is the number of times the content was re-requested from the start after resuming kept failing.
+	Restarts int
+	// ContentTypeChanges is the number of resumed responses rejected for a changed Content-Type.
+	ContentTypeChanges int
+	// IfRangeChecks is the number of requests sent with a stale If-Range to verify that the server honors it.
+	IfRangeChecks int
+	// BlockCacheHits is the number of blocks of WithBlockAlignment or chunks of WithCache served from the cache.
+	BlockCacheHits int
+	// DNSTime, ConnectTime, TLSTime, FirstByteTime and TotalTime are the distributions of the phases
+	// of the recent requests, as in AttemptTiming; a phase skipped by a reused connection is not counted.
+	DNSTime       TimingStats
+	ConnectTime   TimingStats
+	TLSTime       TimingStats
+	FirstByteTime TimingStats
+	TotalTime     TimingStats
+}
+
+// Stats returns the counters collected so far.
+func (s *Seeker) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.statsLocked()
+}
+
+func (s *Seeker) statsLocked() Stats {
+	stats := s.stats
+	stats.DNSTime = newTimingStats(s.timings.dns)
+	stats.ConnectTime = newTimingStats(s.timings.connect)
+	stats.TLSTime = newTimingStats(s.timings.tls)
+	stats.FirstByteTime = newTimingStats(s.timings.firstByte)
+	stats.TotalTime = newTimingStats(s.timings.total)
+	if s.prefetch != nil {
+		for _, r := range s.prefetch.used {
+			stats.PrefetchUsedBytes += r.End - r.Start
+		}
+	}
+	if s.audit != nil {
+		stats.AuditDropped = s.audit.dropped.Load()
+	}
+	return stats
+}
@@ -0,0 +1,102 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSeekCurrentOffsetReusesStream(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(rsc, buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(0, io.SeekCurrent); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(-6, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "World!" {
+		t.Fatalf("got %q, want %q", got, "World!")
+	}
+
+	stats := rsc.Stats()
+	if stats.Requests != 1 {
+		t.Fatalf("got %d requests, want %d", stats.Requests, 1)
+	}
+	if stats.AvoidedRequests != 3 {
+		t.Fatalf("got %d avoided requests, want %d", stats.AvoidedRequests, 3)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if err := rsc.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(rsc, buf); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsc.Release(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf)+string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", string(buf)+string(got), "Hello World!")
+	}
+
+	stats := rsc.Stats()
+	if stats.Releases != 1 || stats.Requests != 2 {
+		t.Fatalf("got %d releases and %d requests, want 1 and 2", stats.Releases, stats.Requests)
+	}
+	if attempt := rsc.CurrentAttempt(); !attempt.Ranged || attempt.Offset != 6 || attempt.Err != nil {
+		t.Fatalf("got %+v, want a clean resume at offset 6", attempt)
+	}
+}
@@ -0,0 +1,57 @@
+package httpseek
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StatusAction tells the Seeker how to handle a response status, overriding the built-in handling.
+type StatusAction int
+
+const (
+	// StatusPassthrough returns the response body to the caller as is,
+	// which is what the Seeker does for statuses it does not know.
+	StatusPassthrough StatusAction = iota
+	// StatusTreatAsOK handles the response like 200 OK: the body is the content from the start
+	// and is rejected for a request at a non-zero offset.
+	StatusTreatAsOK
+	// StatusRetryAfterDelay fails the attempt with a retryable StatusError.
+	// The retry waits for the response's Retry-After, or the regular backoff when it has none.
+	StatusRetryAfterDelay
+	// StatusFail fails the request with a StatusError that is not retried.
+	StatusFail
+)
+
+func (a StatusAction) String() string {
+	switch a {
+	case StatusTreatAsOK:
+		return "treat as ok"
+	case StatusRetryAfterDelay:
+		return "retry after delay"
+	case StatusFail:
+		return "fail"
+	}
+	return "passthrough"
+}
+
+// retryAfter returns the delay requested by a Retry-After header,
+// given in seconds or as an HTTP date, or 0 if there is none.
+func retryAfter(header http.Header) time.Duration {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,68 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusAction(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		action   StatusAction
+		want     string
+		requests int
+		wantErr  bool
+	}{
+		{action: StatusTreatAsOK, want: "staged", requests: 1},
+		{action: StatusRetryAfterDelay, want: "Hello World!", requests: 2},
+		{action: StatusFail, requests: 1, wantErr: true},
+		{action: StatusPassthrough, want: "staged", requests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action.String(), func(t *testing.T) {
+			var requests int
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusAccepted)
+					w.Write([]byte("staged"))
+					return
+				}
+				w.Write([]byte("Hello World!"))
+			}))
+			defer s.Close()
+
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req,
+				WithStatusAction(http.StatusAccepted, tt.action),
+				WithRetry(1, 0),
+			)
+			defer rsc.Close()
+
+			got, err := io.ReadAll(rsc)
+			if tt.wantErr {
+				var statusErr *StatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusAccepted {
+					t.Fatalf("got %v, want status error %d", err, http.StatusAccepted)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			if requests != tt.requests {
+				t.Fatalf("got %d requests, want %d", requests, tt.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+)
+
+// Tail returns the last n bytes of the content, or all of it if it is shorter, with a single suffix range request
+// that works even when the size is not known yet; the size reported by the response becomes the Size.
+// The open stream is left untouched, and the caller must close the returned body.
+func (s *Seeker) Tail(n int64) (io.ReadCloser, error) {
+	if n <= 0 {
+		return nil, errors.New("tail length must be positive")
+	}
+	defer s.flushEvents()
+	r, _, _, err := s.openTail(s.ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// openTail requests the last n bytes of the content with a suffix range,
+// returning the body together with its start offset and the total size.
+func (s *Seeker) openTail(ctx context.Context, n int64) (io.ReadCloser, int64, int64, error) {
+	traceCtx, trace := withTrace(ctx)
+	req := s.req.Clone(traceCtx)
+	req.Header.Set("Range", fmt.Sprintf("bytes=-%d", n))
+	s.mu.Lock()
+	s.pinHeaders(req)
+	s.setIfRange(req)
+	s.stats.Requests++
+	s.mu.Unlock()
+
+	resp, req, _, err := s.roundTrip(req)
+	s.recordTiming(trace)
+	if err != nil {
+		return nil, 0, -1, unwrapTransportError(err)
+	}
+
+	var start int64
+	var size int64
+	if size, ok := unsatisfiedSize(resp.Header.Get(contentRangeKey)); ok && size == 0 {
+		// An empty content has no suffix to send.
+		_ = resp.Body.Close()
+		s.mu.Lock()
+		err = s.checkSize(0)
+		if err == nil && s.size < 0 {
+			s.setSize(0)
+		}
+		s.mu.Unlock()
+		if err != nil {
+			return nil, 0, -1, err
+		}
+		return http.NoBody, 0, 0, nil
+	}
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// The start is whatever the server chose for the suffix, so any is accepted.
+		contentRange := resp.Header.Get(contentRangeKey)
+		var end int64
+		start, end, size, err = getContentLength(contentRange, math.MaxInt64, -1, s.cfg.strictContentRange)
+		if err == nil && size < 0 {
+			err = errors.New("suffix range response does not report the size")
+		}
+		if err == nil && end+1 != size {
+			err = fmt.Errorf("%w: suffix range stops before the end of the content: %s", ErrSizeMismatch, contentRange)
+		}
+	case http.StatusOK:
+		size = resp.ContentLength
+		s.mu.Lock()
+		err = s.checkIfRange(0, req, resp)
+		s.mu.Unlock()
+		if err != nil {
+			break
+		}
+		if size < 0 || size > n {
+			err = ErrCodeForByteRange
+		}
+	default:
+		err = &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	if err == nil {
+		s.mu.Lock()
+		err = s.checkSizeAt(start, resp, size)
+		if err == nil {
+			err = s.checkResponse(start, req, resp)
+		}
+		if err == nil && s.size < 0 {
+			s.setSize(size)
+		}
+		s.mu.Unlock()
+	}
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, 0, -1, err
+	}
+	return resp.Body, start, size, nil
+}
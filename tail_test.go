@@ -0,0 +1,58 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTail(t *testing.T) {
+	ctx := context.Background()
+
+	content := []byte("Hello World! Hello Seeker!")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	for _, tt := range []struct {
+		n    int64
+		want []byte
+	}{
+		{n: 7, want: content[len(content)-7:]},
+		{n: 100, want: content},
+	} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsc := NewSeeker(ctx, s.Client().Transport, req)
+		if rsc.Size() != -1 {
+			t.Fatalf("got size %d before the tail, want -1", rsc.Size())
+		}
+
+		r, err := rsc.Tail(tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Fatalf("tail %d: got %q, want %q", tt.n, got, tt.want)
+		}
+		if rsc.Size() != int64(len(content)) {
+			t.Fatalf("tail %d: got size %d, want %d", tt.n, rsc.Size(), len(content))
+		}
+		if stats := rsc.Stats(); stats.Requests != 1 {
+			t.Fatalf("tail %d: got %d requests, want 1", tt.n, stats.Requests)
+		}
+		rsc.Close()
+	}
+}
@@ -0,0 +1,216 @@
+// Package testutil provides helpers for testing code that reads through httpseek.
+package testutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Step scripts the response of a ReplayTransport to one request.
+type Step struct {
+	// Err, if set without Limit, fails the round trip with Err before any response.
+	Err error
+	// Status, if non-zero, answers with this status and Body instead of the content.
+	Status int
+	// Body is the body sent with Status.
+	Body []byte
+	// Header is added to the response.
+	Header http.Header
+	// IgnoreRange answers with the full content and 200, as a server that does not support ranges.
+	IgnoreRange bool
+	// Limit, if positive, breaks the body after Limit bytes with Err, or io.ErrUnexpectedEOF if Err is nil.
+	Limit int64
+}
+
+// Fail returns a Step failing the round trip with err.
+func Fail(err error) Step {
+	return Step{Err: err}
+}
+
+// Respond returns a Step answering with status and the given header values, given as key, value pairs.
+func Respond(status int, keyValues ...string) Step {
+	header := http.Header{}
+	for i := 0; i+1 < len(keyValues); i += 2 {
+		header.Add(keyValues[i], keyValues[i+1])
+	}
+	return Step{Status: status, Header: header}
+}
+
+// ServeThenFail returns a Step serving n bytes of the requested range and then failing the body with err.
+func ServeThenFail(n int64, err error) Step {
+	return Step{Limit: n, Err: err}
+}
+
+// IgnoreRange returns a Step answering with the full content regardless of Range.
+func IgnoreRange() Step {
+	return Step{IgnoreRange: true}
+}
+
+// Request is a request received by a ReplayTransport.
+type Request struct {
+	Method  string
+	URL     string
+	Range   string
+	IfRange string
+	Header  http.Header
+}
+
+// ReplayTransport is an http.RoundTripper serving a content from memory with a script of failures:
+// the n-th request is answered as the n-th Step says, and the requests after the script are served normally.
+// Served requests honor Range, a single range, and If-Range against ETag.
+// It records every request it receives and is safe for concurrent use.
+type ReplayTransport struct {
+	content []byte
+
+	// ETag, if set before the first request, is sent with every response and compared with If-Range.
+	ETag string
+
+	mu       sync.Mutex
+	steps    []Step
+	requests []Request
+}
+
+// NewReplayTransport returns a ReplayTransport serving content after the given steps.
+func NewReplayTransport(content []byte, steps ...Step) *ReplayTransport {
+	return &ReplayTransport{content: content, steps: steps}
+}
+
+// Requests returns the requests received so far, in order.
+func (t *ReplayTransport) Requests() []Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Request(nil), t.requests...)
+}
+
+// Pending returns the number of steps not played yet.
+func (t *ReplayTransport) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.steps)
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	t.mu.Lock()
+	t.requests = append(t.requests, Request{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Range:   req.Header.Get("Range"),
+		IfRange: req.Header.Get("If-Range"),
+		Header:  req.Header.Clone(),
+	})
+	var step Step
+	if len(t.steps) > 0 {
+		step = t.steps[0]
+		t.steps = t.steps[1:]
+	}
+	t.mu.Unlock()
+
+	if step.Err != nil && step.Limit <= 0 {
+		return nil, step.Err
+	}
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	resp := &http.Response{
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Request:    req,
+	}
+	for key, values := range step.Header {
+		resp.Header[key] = append([]string(nil), values...)
+	}
+	if t.ETag != "" {
+		resp.Header.Set("ETag", t.ETag)
+	}
+
+	if step.Status != 0 {
+		resp.StatusCode = step.Status
+		resp.ContentLength = int64(len(step.Body))
+		resp.Body = io.NopCloser(bytes.NewReader(step.Body))
+		return resp, nil
+	}
+
+	resp.Header.Set("Accept-Ranges", "bytes")
+	body := t.content
+	resp.StatusCode = http.StatusOK
+	rangeHeader := req.Header.Get("Range")
+	if ifRange := req.Header.Get("If-Range"); ifRange != "" && ifRange != t.ETag {
+		rangeHeader = ""
+	}
+	if rangeHeader != "" && !step.IgnoreRange {
+		start, end, ok := parseRange(rangeHeader, int64(len(t.content)))
+		if !ok {
+			resp.StatusCode = http.StatusRequestedRangeNotSatisfiable
+			resp.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", len(t.content)))
+			resp.Body = http.NoBody
+			return resp, nil
+		}
+		resp.StatusCode = http.StatusPartialContent
+		resp.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end-1, len(t.content)))
+		body = t.content[start:end]
+	}
+	resp.ContentLength = int64(len(body))
+	resp.Header.Set("Content-Length", strconv.Itoa(len(body)))
+
+	var r io.Reader = bytes.NewReader(body)
+	if step.Limit > 0 && step.Limit < int64(len(body)) {
+		err := step.Err
+		if err == nil {
+			err = io.ErrUnexpectedEOF
+		}
+		r = io.MultiReader(io.LimitReader(r, step.Limit), errReader{err: err})
+	}
+	resp.Body = io.NopCloser(r)
+	return resp, nil
+}
+
+// parseRange parses a single byte range of a content of size bytes into [start, end).
+func parseRange(header string, size int64) (int64, int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes=")
+	if !ok || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	first, last, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	if first == "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		return max(size-n, 0), size, size > 0
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start >= size {
+		return 0, 0, false
+	}
+	end := size
+	if last != "" {
+		n, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || n < start {
+			return 0, 0, false
+		}
+		end = min(n+1, size)
+	}
+	return start, end, true
+}
+
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,80 @@
+package testutil
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestReplayTransport(t *testing.T) {
+	reset := errors.New("connection reset")
+	transport := NewReplayTransport([]byte("Hello World!"),
+		ServeThenFail(5, reset),
+		Respond(http.StatusServiceUnavailable, "Retry-After", "2"),
+		IgnoreRange(),
+		Fail(reset),
+	)
+	transport.ETag = `"v1"`
+
+	get := func(rangeHeader, ifRange string) (*http.Response, string, error) {
+		req, err := http.NewRequest(http.MethodGet, "http://example.com/test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rangeHeader != "" {
+			req.Header.Set("Range", rangeHeader)
+		}
+		if ifRange != "" {
+			req.Header.Set("If-Range", ifRange)
+		}
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		return resp, string(body), err
+	}
+
+	if _, body, err := get("", ""); !errors.Is(err, reset) || body != "Hello" {
+		t.Fatalf("got %q, %v, want %q, %v", body, err, "Hello", reset)
+	}
+	if resp, _, _ := get("bytes=5-", `"v1"`); resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") != "2" {
+		t.Fatalf("got status %d, want %d with Retry-After", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	if resp, body, _ := get("bytes=5-", `"v1"`); resp.StatusCode != http.StatusOK || body != "Hello World!" {
+		t.Fatalf("got %d %q, want the full content", resp.StatusCode, body)
+	}
+	if _, _, err := get("bytes=5-", `"v1"`); !errors.Is(err, reset) {
+		t.Fatalf("got %v, want %v", err, reset)
+	}
+	if transport.Pending() != 0 {
+		t.Fatalf("got %d pending steps, want none", transport.Pending())
+	}
+
+	for _, test := range []struct {
+		rangeHeader, ifRange string
+		status               int
+		body                 string
+	}{
+		{rangeHeader: "bytes=6-", ifRange: `"v1"`, status: http.StatusPartialContent, body: "World!"},
+		{rangeHeader: "bytes=0-4", status: http.StatusPartialContent, body: "Hello"},
+		{rangeHeader: "bytes=-6", status: http.StatusPartialContent, body: "World!"},
+		{rangeHeader: "bytes=6-", ifRange: `"v0"`, status: http.StatusOK, body: "Hello World!"},
+		{rangeHeader: "bytes=12-", status: http.StatusRequestedRangeNotSatisfiable},
+	} {
+		resp, body, err := get(test.rangeHeader, test.ifRange)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != test.status || body != test.body {
+			t.Fatalf("%s: got %d %q, want %d %q", test.rangeHeader, resp.StatusCode, body, test.status, test.body)
+		}
+	}
+
+	requests := transport.Requests()
+	if len(requests) != 9 || requests[1].Range != "bytes=5-" || requests[1].IfRange != `"v1"` {
+		t.Fatalf("got requests %+v", requests)
+	}
+}
@@ -0,0 +1,57 @@
+package httpseek
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket of bytes for WithBytesPerSecond, refilled at rate bytes per second up to burst.
+// It outlives the bodies of the Seeker, so reconnects and resumes draw from the same bucket.
+type rateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter returns a full bucket refilled at bytesPerSecond, holding a tenth of a second of bytes.
+func newRateLimiter(bytesPerSecond int64) *rateLimiter {
+	burst := max(float64(bytesPerSecond)/10, 1)
+	return &rateLimiter{
+		rate:   float64(bytesPerSecond),
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// wait waits under ctx until at least one byte may be read and returns how many, at most n.
+func (l *rateLimiter) wait(ctx context.Context, n int) (int, error) {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		if l.tokens >= 1 {
+			allowed := min(n, int(l.tokens))
+			l.mu.Unlock()
+			return allowed, nil
+		}
+		delay := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		if err := sleep(ctx, delay); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// take removes the n bytes read from the bucket.
+func (l *rateLimiter) take(n int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens -= float64(n)
+}
@@ -0,0 +1,69 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestBytesPerSecond(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 300)
+	transport := testutil.NewReplayTransport(content, testutil.ServeThenFail(1500, errors.New("connection reset")))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithBytesPerSecond(10000))
+	defer rsc.Close()
+
+	// A burst of 1000 bytes, then 2000 bytes at 10000 per second, including those of the resume.
+	start := time.Now()
+	got, err := io.ReadAll(NewMustReader(rsc, nil))
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	if len(transport.Requests()) != 2 {
+		t.Fatalf("got %d requests, want a resume", len(transport.Requests()))
+	}
+	if elapsed < 180*time.Millisecond || elapsed > 2*time.Second {
+		t.Fatalf("read in %s, want about 200ms", elapsed)
+	}
+}
+
+func TestBytesPerSecondCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	transport := testutil.NewReplayTransport([]byte("Hello World!"))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/file", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, transport, req, WithBytesPerSecond(1))
+	defer rsc.Close()
+
+	p := make([]byte, 12)
+	if n, err := rsc.Read(p); n != 1 || err != nil {
+		t.Fatalf("got %d bytes and error %v, want the burst of 1 byte", n, err)
+	}
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	if _, err := rsc.Read(p); !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("canceled after %s, want about 20ms", elapsed)
+	}
+}
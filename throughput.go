@@ -0,0 +1,47 @@
+package httpseek
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTooSlow is recorded as the error of an attempt aborted because its throughput dropped below the configured minimum.
+var ErrTooSlow = errors.New("throughput below the configured minimum")
+
+type throughputSample struct {
+	n    int
+	busy time.Duration
+}
+
+// throughputMonitor measures the rate at which the server delivers bytes.
+// Only the time spent waiting inside the body's Read is counted,
+// so a consumer that is slow to call Read does not look like a slow server.
+type throughputMonitor struct {
+	samples []throughputSample
+	bytes   int64
+	busy    time.Duration
+}
+
+// add records that n bytes were read in busy, and reports whether the average rate
+// over the last window of busy time dropped below minRate bytes per second.
+// Nothing is reported before a full window has been observed.
+func (m *throughputMonitor) add(n int, busy time.Duration, minRate int64, window time.Duration) bool {
+	m.samples = append(m.samples, throughputSample{n: n, busy: busy})
+	m.bytes += int64(n)
+	m.busy += busy
+	for len(m.samples) > 1 && m.busy-m.samples[0].busy >= window {
+		m.bytes -= int64(m.samples[0].n)
+		m.busy -= m.samples[0].busy
+		m.samples = m.samples[1:]
+	}
+	if m.busy < window {
+		return false
+	}
+	return float64(m.bytes)/m.busy.Seconds() < float64(minRate)
+}
+
+func (m *throughputMonitor) reset() {
+	m.samples = m.samples[:0]
+	m.bytes = 0
+	m.busy = 0
+}
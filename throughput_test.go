@@ -0,0 +1,93 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMinThroughput(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 10)
+
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) > 1 {
+			http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+			return
+		}
+		// Trickle the first response one byte at a time.
+		w.Header().Set("Content-Length", "100")
+		for _, b := range content {
+			if _, err := w.Write([]byte{b}); err != nil {
+				return
+			}
+			w.(http.Flusher).Flush()
+			time.Sleep(10 * time.Millisecond)
+		}
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithMinThroughput(1000, 50*time.Millisecond))
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	if rsc.Stats().SlowAborts != 1 {
+		t.Fatalf("got %d slow aborts, want %d", rsc.Stats().SlowAborts, 1)
+	}
+	if attempts := rsc.Attempts(); attempts[0].Err != ErrTooSlow {
+		t.Fatalf("got %v for the first attempt, want %v", attempts[0].Err, ErrTooSlow)
+	}
+}
+
+func TestMinThroughputSlowConsumer(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 10)
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithMinThroughput(1000, 50*time.Millisecond))
+	defer rsc.Close()
+
+	var got []byte
+	buf := make([]byte, 10)
+	for {
+		n, err := rsc.Read(buf)
+		got = append(got, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	if rsc.Stats().SlowAborts != 0 || rsc.Stats().Requests != 1 {
+		t.Fatalf("got %d slow aborts in %d requests, want a single uninterrupted request", rsc.Stats().SlowAborts, rsc.Stats().Requests)
+	}
+}
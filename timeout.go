@@ -0,0 +1,86 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrBudgetExceeded is the kind of a TimeoutError returned when the WithMaxElapsed budget has passed.
+	ErrBudgetExceeded = errors.New("elapsed-time budget exceeded")
+	// ErrAttemptTimeout is the kind of a TimeoutError returned when a request got no response
+	// within the timeout of WithRequestTimeout.
+	ErrAttemptTimeout = errors.New("request timed out")
+)
+
+// TimeoutError is returned when a timeout configured on the Seeker fires.
+// It matches errors.Is with its Kind and with context.DeadlineExceeded.
+type TimeoutError struct {
+	// Kind identifies the timeout, such as ErrBudgetExceeded.
+	Kind error
+	// Timeout is the configured duration.
+	Timeout time.Duration
+	// Offset is the offset of the transfer when the timeout fired.
+	Offset int64
+	// Err is the error of the last attempt, or nil.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%v after %s at offset %d", e.Kind, e.Timeout, e.Offset)
+	}
+	return fmt.Sprintf("%v after %s at offset %d: %v", e.Kind, e.Timeout, e.Offset, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == e.Kind || target == context.DeadlineExceeded
+}
+
+// attemptTimer cancels a request that got no response within the timeout of WithRequestTimeout.
+type attemptTimer struct {
+	timeout time.Duration
+	timer   *time.Timer
+	cancel  context.CancelFunc
+}
+
+// startAttempt returns the context of a request attempt and its timer, or ctx and nil without a timeout.
+func (c *config) startAttempt(ctx context.Context) (context.Context, *attemptTimer) {
+	if c.requestTimeout <= 0 {
+		return ctx, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, &attemptTimer{
+		timeout: c.requestTimeout,
+		timer:   time.AfterFunc(c.requestTimeout, cancel),
+		cancel:  cancel,
+	}
+}
+
+// stop stops the timer once the request returned. If the timer fired first, the response is closed
+// and a TimeoutError of kind ErrAttemptTimeout at offset is returned; otherwise the body is read on
+// under the context of the request, which is released when the body is closed.
+func (t *attemptTimer) stop(offset int64, resp *http.Response, err error) (*http.Response, error) {
+	if t == nil {
+		return resp, err
+	}
+	if !t.timer.Stop() {
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		return nil, &TimeoutError{Kind: ErrAttemptTimeout, Timeout: t.timeout, Offset: offset, Err: err}
+	}
+	if err != nil {
+		t.cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: t.cancel}
+	return resp, nil
+}
@@ -0,0 +1,103 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			// No response until the request is canceled.
+			<-r.Context().Done()
+			return
+		case 2:
+			// Headers in time, then a body slower than the timeout.
+			w.Header().Set("Content-Length", "1000")
+			w.WriteHeader(http.StatusOK)
+			for i := 0; i < 4; i++ {
+				w.Write(content[i*250 : (i+1)*250])
+				w.(http.Flusher).Flush()
+				time.Sleep(30 * time.Millisecond)
+			}
+		}
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithRequestTimeout(50*time.Millisecond), WithRetry(0, time.Millisecond))
+	defer rsc.Close()
+
+	var handled []error
+	got, err := io.ReadAll(NewMustReader(rsc, func(retry int, err error) error {
+		handled = append(handled, err)
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	var timeoutErr *TimeoutError
+	if len(handled) != 1 || !errors.Is(handled[0], ErrAttemptTimeout) || !errors.As(handled[0], &timeoutErr) || timeoutErr.Timeout != 50*time.Millisecond {
+		t.Fatalf("got errors %v, want one request timeout", handled)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Fatalf("got %d requests, want 2", n)
+	}
+}
+
+func TestRequestTimeoutRetry(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		w.Write(content)
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reasons []RetryReason
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req,
+		WithRequestTimeout(50*time.Millisecond),
+		WithRetry(1, time.Millisecond),
+		WithRetryHandler(func(info RetryInfo) error {
+			reasons = append(reasons, info.Reason)
+			return nil
+		}),
+	)
+	defer rsc.Close()
+
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Fatalf("got %q, want %q", got, content)
+	}
+	if len(reasons) != 1 || reasons[0] != RetryAttemptTimeout {
+		t.Fatalf("got retry reasons %v, want %v", reasons, RetryAttemptTimeout)
+	}
+}
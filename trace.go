@@ -0,0 +1,189 @@
+package httpseek
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxTimingSamples bounds the number of recent requests whose timings are aggregated into Stats.
+const maxTimingSamples = 1024
+
+// AttemptTiming is the timing breakdown of a request measured with httptrace.
+// A phase that did not happen, such as the DNS lookup, connect and TLS handshake
+// of a request sent over a reused connection, is zero.
+type AttemptTiming struct {
+	// DNS is the time spent resolving the host.
+	DNS time.Duration
+	// Connect is the time spent establishing the TCP connection.
+	Connect time.Duration
+	// TLS is the time spent in the TLS handshake.
+	TLS time.Duration
+	// FirstByte is the time from sending the request to the first byte of the response.
+	FirstByte time.Duration
+	// Total is the time from sending the request to its response, including redirects.
+	Total time.Duration
+}
+
+// TimingStats is the distribution of a request phase over the recent requests that went through it.
+type TimingStats struct {
+	// Count is the number of requests measured.
+	Count int
+	Min   time.Duration
+	// Median is the lower median.
+	Median time.Duration
+	Max    time.Duration
+}
+
+func newTimingStats(samples []time.Duration) TimingStats {
+	if len(samples) == 0 {
+		return TimingStats{}
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	return TimingStats{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Median: sorted[(len(sorted)-1)/2],
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// timingSamples keeps the recent timings of each phase.
+type timingSamples struct {
+	dns, connect, tls, firstByte, total []time.Duration
+}
+
+func addTimingSample(samples []time.Duration, d time.Duration) []time.Duration {
+	if len(samples) == maxTimingSamples {
+		samples = samples[:copy(samples, samples[1:])]
+	}
+	return append(samples, d)
+}
+
+// add records the phases of t that happened. It must be called with s.mu held.
+func (t *timingSamples) add(timing AttemptTiming) {
+	if timing.DNS > 0 {
+		t.dns = addTimingSample(t.dns, timing.DNS)
+	}
+	if timing.Connect > 0 {
+		t.connect = addTimingSample(t.connect, timing.Connect)
+	}
+	if timing.TLS > 0 {
+		t.tls = addTimingSample(t.tls, timing.TLS)
+	}
+	if timing.FirstByte > 0 {
+		t.firstByte = addTimingSample(t.firstByte, timing.FirstByte)
+	}
+	t.total = addTimingSample(t.total, timing.Total)
+}
+
+type traceKey struct{}
+
+// requestTrace measures the phases of a request. Its hooks may be called from the goroutines
+// of the transport and of hedged copies of the request, so it has its own lock.
+// Only the first occurrence of each phase is measured.
+type requestTrace struct {
+	mu                                  sync.Mutex
+	start                               time.Time
+	dnsStart, connectStart, tlsStart    time.Time
+	dns, connect, tls, firstByte, total time.Duration
+}
+
+// withTrace returns ctx with a ClientTrace measuring the request sent with it, merged with any trace
+// the caller attached to ctx. It returns a nil requestTrace if ctx is already measured, so that a request
+// derived from a measured one is not measured twice.
+func withTrace(ctx context.Context) (context.Context, *requestTrace) {
+	if ctx.Value(traceKey{}) != nil {
+		return ctx, nil
+	}
+	t := &requestTrace{start: time.Now()}
+	ctx = context.WithValue(ctx, traceKey{}, t)
+	return httptrace.WithClientTrace(ctx, t.clientTrace()), t
+}
+
+func (t *requestTrace) clientTrace() *httptrace.ClientTrace {
+	begin := func(at *time.Time) {
+		t.mu.Lock()
+		if at.IsZero() {
+			*at = time.Now()
+		}
+		t.mu.Unlock()
+	}
+	end := func(start *time.Time, d *time.Duration) {
+		t.mu.Lock()
+		if !start.IsZero() && *d == 0 {
+			*d = time.Since(*start)
+		}
+		t.mu.Unlock()
+	}
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) { begin(&t.dnsStart) },
+		DNSDone:  func(httptrace.DNSDoneInfo) { end(&t.dnsStart, &t.dns) },
+		ConnectStart: func(string, string) {
+			begin(&t.connectStart)
+		},
+		ConnectDone: func(string, string, error) {
+			end(&t.connectStart, &t.connect)
+		},
+		TLSHandshakeStart: func() { begin(&t.tlsStart) },
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			end(&t.tlsStart, &t.tls)
+		},
+		GotFirstResponseByte: func() { end(&t.start, &t.firstByte) },
+	}
+}
+
+// finish returns the timing of the request, which ended now, or the zero AttemptTiming if t is nil.
+func (t *requestTrace) finish() AttemptTiming {
+	if t == nil {
+		return AttemptTiming{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.total == 0 {
+		t.total = time.Since(t.start)
+	}
+	return AttemptTiming{DNS: t.dns, Connect: t.connect, TLS: t.tls, FirstByte: t.firstByte, Total: t.total}
+}
+
+// recordTiming adds the timing of a request that ended now to the Stats, unless t is nil.
+func (s *Seeker) recordTiming(t *requestTrace) {
+	if t == nil {
+		return
+	}
+	timing := t.finish()
+	s.mu.Lock()
+	s.timings.add(timing)
+	s.mu.Unlock()
+}
+
+// TraceInfo identifies the request a ClientTrace of WithClientTrace is attached to.
+type TraceInfo struct {
+	// Offset is the first byte requested, 0 without a Range and -1 for a suffix range,
+	// so that traces can be matched to the Seeks that caused them.
+	Offset int64
+	// Hop is 0 for the request itself and n for the request sent for its n-th redirect.
+	Hop int
+	// URL is the URL the hop is sent to.
+	URL *url.URL
+}
+
+// traceHop returns req with the ClientTrace of WithClientTrace for the hop attached to its context,
+// or req itself if there is none. The trace is only attached to the request sent, so the next hop
+// derived from req does not report to it.
+func (c *config) traceHop(req *http.Request, hop int) *http.Request {
+	if c.clientTrace == nil {
+		return req
+	}
+	trace := c.clientTrace(TraceInfo{Offset: requestOffset(req), Hop: hop, URL: req.URL})
+	if trace == nil {
+		return req
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+}
@@ -0,0 +1,142 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTrace(t *testing.T) {
+	var firstBytes atomic.Int32
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		GotFirstResponseByte: func() { firstBytes.Add(1) },
+	})
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := io.ReadAll(rsc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(rsc); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := firstBytes.Load(); n != 2 {
+		t.Fatalf("got %d calls of the caller's trace, want %d", n, 2)
+	}
+	stats := rsc.Stats()
+	if stats.FirstByteTime.Count != 2 || stats.TotalTime.Count != 2 {
+		t.Fatalf("got %d first byte and %d total timings, want 2 each", stats.FirstByteTime.Count, stats.TotalTime.Count)
+	}
+	if stats.ConnectTime.Count != 1 {
+		t.Fatalf("got %d connect timings, want 1 for the reused connection", stats.ConnectTime.Count)
+	}
+	if stats.TotalTime.Min <= 0 || stats.TotalTime.Min > stats.TotalTime.Median || stats.TotalTime.Median > stats.TotalTime.Max {
+		t.Fatalf("got inconsistent total timings %+v", stats.TotalTime)
+	}
+	if timing := rsc.CurrentAttempt().Timing; timing.FirstByte <= 0 || timing.Total < timing.FirstByte {
+		t.Fatalf("got attempt timing %+v", timing)
+	}
+}
+
+func TestTraceNotRegisteredTwice(t *testing.T) {
+	ctx, trace := withTrace(context.Background())
+	if trace == nil {
+		t.Fatal("expected a trace")
+	}
+	if _, again := withTrace(ctx); again != nil {
+		t.Fatal("expected no second trace for a measured context")
+	}
+}
+
+func TestTimingStats(t *testing.T) {
+	got := newTimingStats([]time.Duration{4, 1, 3, 2})
+	want := TimingStats{Count: 4, Min: 1, Median: 2, Max: 4}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestClientTrace(t *testing.T) {
+	ctx := context.Background()
+	content := bytes.Repeat([]byte("0123456789"), 100)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	type entry struct {
+		offset     int64
+		hop        int
+		path       string
+		firstBytes int
+	}
+	var mu sync.Mutex
+	var entries []*entry
+	newTrace := func(info TraceInfo) *httptrace.ClientTrace {
+		e := &entry{offset: info.Offset, hop: info.Hop, path: info.URL.Path}
+		mu.Lock()
+		entries = append(entries, e)
+		mu.Unlock()
+		return &httptrace.ClientTrace{
+			GotFirstResponseByte: func() {
+				mu.Lock()
+				e.firstBytes++
+				mu.Unlock()
+			},
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL+"/old", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithClientTrace(newTrace))
+	defer rsc.Close()
+
+	if _, err := io.ReadFull(rsc, make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsc.Seek(500, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(rsc, make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []entry{{0, 0, "/old", 1}, {0, 1, "/new", 1}, {500, 0, "/old", 1}, {500, 1, "/new", 1}}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d traces, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if *e != want[i] {
+			t.Errorf("got trace %d %+v, want %+v", i, *e, want[i])
+		}
+	}
+}
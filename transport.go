@@ -7,13 +7,21 @@ import (
 type mustReaderTransport struct {
 	baseTransport http.RoundTripper
 	errorHandler  func(*http.Request, int, error) error
+	opts          []Option
 }
 
 // NewMustReaderTransport returns a transport that will retry reading with partial byte ranges if the underlying transport returns an error.
 func NewMustReaderTransport(baseTransport http.RoundTripper, errorHandler func(*http.Request, int, error) error) http.RoundTripper {
+	return NewMustReaderTransportWithOptions(baseTransport, errorHandler)
+}
+
+// NewMustReaderTransportWithOptions is NewMustReaderTransport with options applied to the Seeker
+// reading each response, such as WithProgress. Redirects are always left to the http.Client.
+func NewMustReaderTransportWithOptions(baseTransport http.RoundTripper, errorHandler func(*http.Request, int, error) error, opts ...Option) http.RoundTripper {
 	return &mustReaderTransport{
 		baseTransport: baseTransport,
 		errorHandler:  errorHandler,
+		opts:          opts,
 	}
 }
 
@@ -24,7 +32,9 @@ func (t *mustReaderTransport) RoundTrip(r *http.Request) (resp *http.Response, e
 	}
 
 	var retry = 0
-	rsc := NewSeeker(r.Context(), t.baseTransport, r)
+	// Redirects are left to the http.Client so its redirect policy applies.
+	opts := append(t.opts[:len(t.opts):len(t.opts)], WithFollowRedirects(false))
+	rsc := NewSeekerWithOptions(r.Context(), t.baseTransport, r, opts...)
 	for {
 		resp, err = rsc.Response()
 		if err == nil {
@@ -34,3 +34,34 @@ func TestMustReadTransport(t *testing.T) {
 		t.Fatalf("got %q, want %q", got, "Hello World!")
 	}
 }
+
+func TestMustReadTransportRedirect(t *testing.T) {
+	s := newRedirectServer()
+	defer s.Close()
+
+	var redirects int
+	client := &http.Client{
+		Transport: NewMustReaderTransport(s.Client().Transport, nil),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			redirects++
+			return nil
+		},
+	}
+
+	resp, err := client.Get(s.URL + "/source")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "Hello World!" {
+		t.Fatalf("got %q, want %q", got, "Hello World!")
+	}
+	if redirects != 1 {
+		t.Fatalf("got %d redirects through the client policy, want %d", redirects, 1)
+	}
+}
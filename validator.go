@@ -0,0 +1,93 @@
+package httpseek
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// ErrNoValidator is returned by the first response under ValidatorRequired
+// when it carries neither a strong ETag nor Last-Modified.
+var ErrNoValidator = errors.New("response has neither a strong ETag nor Last-Modified")
+
+// ValidatorPolicy selects the validator sent with If-Range on range requests after the first response.
+type ValidatorPolicy int
+
+const (
+	// ValidatorBestEffort uses the ETag, or Last-Modified if there is no strong ETag,
+	// and sends unconditional requests if there is neither.
+	ValidatorBestEffort ValidatorPolicy = iota
+	// ValidatorETagOnly uses only a strong ETag.
+	ValidatorETagOnly
+	// ValidatorRequired is ValidatorBestEffort, but fails the first response with ErrNoValidator
+	// if it has no validator, since resumes could then not detect a changed content.
+	ValidatorRequired
+)
+
+func (p ValidatorPolicy) String() string {
+	switch p {
+	case ValidatorBestEffort:
+		return "best-effort"
+	case ValidatorETagOnly:
+		return "etag-only"
+	case ValidatorRequired:
+		return "required"
+	}
+	return "unknown"
+}
+
+// IfRangeValidator returns the validator sent with If-Range on range requests,
+// the ETag or Last-Modified of the first response, or "" if they are unconditional.
+func (s *Seeker) IfRangeValidator() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ifRangeValidator()
+}
+
+// ifRangeValidator chooses the If-Range validator by the validator policy.
+// It must be called with s.mu held.
+func (s *Seeker) ifRangeValidator() string {
+	return ifRangeValidator(s.cfg.validatorPolicy, s.etag, s.lastModified)
+}
+
+// ifRangeValidator chooses the If-Range validator among etag and lastModified by policy.
+// Weak ETags cannot be used with If-Range.
+func ifRangeValidator(policy ValidatorPolicy, etag, lastModified string) string {
+	if etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	if policy != ValidatorETagOnly {
+		return lastModified
+	}
+	return ""
+}
+
+// setIfRange makes a range request conditional on the validator of the first response, so that a server holding
+// another version answers with its full content instead of splicing its bytes into the stream.
+// It must be called with s.mu held.
+func (s *Seeker) setIfRange(req *http.Request) {
+	if req.Header.Get("Range") == "" || s.polling {
+		return
+	}
+	if v := s.ifRangeValidator(); v != "" {
+		req.Header.Set("If-Range", v)
+	}
+}
+
+// checkIfRange returns a ContentChangedError if a request made conditional by setIfRange
+// was answered with the full content of another version. It must be called with s.mu held.
+func (s *Seeker) checkIfRange(offset int64, req *http.Request, resp *http.Response) error {
+	ifRange := req.Header.Get("If-Range")
+	if ifRange == "" || resp.StatusCode != http.StatusOK {
+		return nil
+	}
+	field := "ETag"
+	if !strings.HasPrefix(ifRange, `"`) {
+		field = "Last-Modified"
+	}
+	value := resp.Header.Get(field)
+	if value == "" || value == ifRange {
+		return nil
+	}
+	return s.changedBy(&ContentChangedError{Offset: offset, Field: field, Old: ifRange, New: value}, resp, resp.ContentLength)
+}
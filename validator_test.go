@@ -0,0 +1,105 @@
+package httpseek
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestValidatorPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	lastModified := modTime.Format(http.TimeFormat)
+
+	var changed atomic.Bool
+	var mut sync.Mutex
+	var ifRange string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mut.Lock()
+		ifRange = r.Header.Get("If-Range")
+		mut.Unlock()
+		content, mod := "Hello World!", modTime
+		if changed.Load() {
+			content, mod = "Hello Again!", modTime.Add(time.Hour)
+		}
+		http.ServeContent(w, r, "test", mod, strings.NewReader(content))
+	}))
+	defer s.Close()
+
+	for _, tt := range []struct {
+		policy      ValidatorPolicy
+		wantIfRange string
+	}{
+		{policy: ValidatorBestEffort, wantIfRange: lastModified},
+		{policy: ValidatorRequired, wantIfRange: lastModified},
+		{policy: ValidatorETagOnly, wantIfRange: ""},
+	} {
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			changed.Store(false)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithValidatorPolicy(tt.policy))
+			defer rsc.Close()
+
+			if _, err := io.ReadFull(rsc, make([]byte, 3)); err != nil {
+				t.Fatal(err)
+			}
+			if got := rsc.IfRangeValidator(); got != tt.wantIfRange {
+				t.Fatalf("got validator %q, want %q", got, tt.wantIfRange)
+			}
+
+			changed.Store(true)
+			if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+				t.Fatal(err)
+			}
+			_, err = rsc.Read(make([]byte, 1))
+			mut.Lock()
+			gotIfRange := ifRange
+			mut.Unlock()
+			if gotIfRange != tt.wantIfRange {
+				t.Fatalf("got If-Range %q, want %q", gotIfRange, tt.wantIfRange)
+			}
+			if tt.wantIfRange == "" {
+				// Without a validator the change goes unnoticed.
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			var changedErr *ContentChangedError
+			if !errors.As(err, &changedErr) || changedErr.Field != "Last-Modified" {
+				t.Fatalf("got %v, want a Last-Modified change", err)
+			}
+		})
+	}
+}
+
+func TestValidatorRequired(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, strings.NewReader("Hello World!"))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithValidatorPolicy(ValidatorRequired))
+	defer rsc.Close()
+
+	if _, err := rsc.Read(make([]byte, 1)); !errors.Is(err, ErrNoValidator) {
+		t.Fatalf("got %v, want %v", err, ErrNoValidator)
+	}
+}
@@ -0,0 +1,110 @@
+package httpseek
+
+import (
+	"bytes"
+	"crypto"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// ErrNotVerified is returned at the end of the content by a reader from NewVerifyingReader
+// when a seek skipped bytes that were never read, so the digest could not be computed.
+var ErrNotVerified = errors.New("content not verified: a seek skipped bytes that were never read")
+
+// DigestError is returned at the end of the content by a reader from NewVerifyingReader,
+// or by Read with WithDigestVerification, when the digest of the content does not match the expected one.
+type DigestError struct {
+	Hash     crypto.Hash
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *DigestError) Error() string {
+	return fmt.Sprintf("%v digest mismatch: expected %x, got %x", e.Hash, e.Expected, e.Actual)
+}
+
+func (e *DigestError) Is(target error) bool {
+	return target == ErrDigestMismatch
+}
+
+// orderedHash hashes the bytes of a content once, in order from offset 0, whatever order they are delivered in.
+type orderedHash struct {
+	h hash.Hash
+	// hashed is the number of bytes from the start of the content written to h.
+	hashed int64
+}
+
+// write hashes the bytes of p, delivered at start, that extend the bytes hashed so far.
+// Bytes delivered again, or past a gap that was never delivered, are not hashed.
+func (o *orderedHash) write(start int64, p []byte) {
+	if end := start + int64(len(p)); start <= o.hashed && end > o.hashed {
+		o.h.Write(p[o.hashed-start:])
+		o.hashed = end
+	}
+}
+
+type verifyingReader struct {
+	rs       io.ReadSeeker
+	hash     crypto.Hash
+	sum      orderedHash
+	expected []byte
+	// offset is the offset of the next byte read from rs.
+	offset int64
+}
+
+// NewVerifyingReader returns a reader that hashes the content with h as it is read from the start
+// and, at the end of the content, returns a *DigestError matching ErrDigestMismatch instead of io.EOF
+// if the digest is not expected.
+//
+// Bytes are hashed once, in order: bytes read again after seeking back, or after a failed Read
+// retried by NewMustReader, are not hashed twice. A seek past the bytes read so far leaves a gap
+// that is never hashed, and the end of the content returns ErrNotVerified unless the gap is read
+// after seeking back. rs must return the bytes at its offset from Read, as a Seeker does across resumes.
+func NewVerifyingReader(rs io.ReadSeeker, h crypto.Hash, expected []byte) io.ReadSeeker {
+	r := &verifyingReader{
+		rs:       rs,
+		hash:     h,
+		expected: expected,
+	}
+	if h.Available() {
+		r.sum.h = h.New()
+	}
+	return r
+}
+
+// Read reads from the wrapped reader, hashing the bytes not hashed yet.
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	if r.sum.h == nil {
+		return 0, fmt.Errorf("hash %v is not available", r.hash)
+	}
+	n, err := r.rs.Read(p)
+	r.sum.write(r.offset, p[:n])
+	r.offset += int64(n)
+	if err == io.EOF {
+		err = r.verify()
+	}
+	return n, err
+}
+
+// verify checks the digest once the end of the content is reached at r.offset.
+func (r *verifyingReader) verify() error {
+	if r.sum.hashed != r.offset {
+		return ErrNotVerified
+	}
+	if sum := r.sum.h.Sum(nil); !bytes.Equal(sum, r.expected) {
+		return &DigestError{Hash: r.hash, Expected: r.expected, Actual: sum}
+	}
+	return io.EOF
+}
+
+// Seek seeks the wrapped reader. The hashed bytes are kept, so only bytes past them are hashed when read.
+func (r *verifyingReader) Seek(offset int64, whence int) (int64, error) {
+	n, err := r.rs.Seek(offset, whence)
+	if err != nil {
+		return n, err
+	}
+	r.offset = n
+	return n, nil
+}
@@ -0,0 +1,85 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/wzshiming/httpseek/testutil"
+)
+
+func TestVerifyingReader(t *testing.T) {
+	ctx := context.Background()
+	content := []byte("Hello World!")
+	sum := sha256.Sum256(content)
+
+	for _, expected := range [][]byte{sum[:], make([]byte, sha256.Size)} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transport := testutil.NewReplayTransport(content, testutil.ServeThenFail(5, errors.New("connection reset")))
+		rsc := NewSeekerWithOptions(ctx, transport, req, WithRetry(0, time.Millisecond))
+
+		r := NewMustReader(NewVerifyingReader(rsc, crypto.SHA256, expected), func(retry int, err error) error {
+			if retry > 0 {
+				return err
+			}
+			return nil
+		})
+		data, err := io.ReadAll(r)
+		if string(data) != string(content) {
+			t.Fatalf("got %q, want %q", data, content)
+		}
+		if bytes.Equal(expected, sum[:]) {
+			if err != nil {
+				t.Fatalf("got %v with the right digest", err)
+			}
+		} else {
+			var digestErr *DigestError
+			if !errors.As(err, &digestErr) || !errors.Is(err, ErrDigestMismatch) || !bytes.Equal(digestErr.Actual, sum[:]) {
+				t.Fatalf("got %v with a wrong digest, want a DigestError", err)
+			}
+		}
+		rsc.Close()
+	}
+}
+
+func TestVerifyingReaderSeek(t *testing.T) {
+	content := []byte("Hello World!")
+	sum := sha256.Sum256(content)
+	r := NewVerifyingReader(bytes.NewReader(content), crypto.SHA256, sum[:])
+
+	buf := make([]byte, 6)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		t.Fatal(err)
+	}
+	// Bytes read again after seeking back are not hashed twice.
+	if _, err := r.Seek(2, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(r, buf[:2]); err != nil {
+		t.Fatal(err)
+	}
+	// Skipping bytes never read leaves the content unverified.
+	if _, err := r.Seek(8, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(r); !errors.Is(err, ErrNotVerified) {
+		t.Fatalf("got %v after skipping bytes, want %v", err, ErrNotVerified)
+	}
+	// Reading the skipped bytes verifies the content again.
+	if _, err := r.Seek(6, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := io.ReadAll(r)
+	if err != nil || string(rest) != "World!" {
+		t.Fatalf("got %q, %v after reading the skipped bytes, want %q", rest, err, "World!")
+	}
+}
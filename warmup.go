@@ -0,0 +1,60 @@
+package httpseek
+
+import (
+	"io"
+	"net/http"
+)
+
+// warmup is the first request of the content, opened in the background by WithWarmup.
+type warmup struct {
+	done chan struct{}
+	r    io.ReadCloser
+	size int64
+	resp *http.Response
+	err  error
+}
+
+// startWarmup opens the stream at offset 0 in the background under the stream context.
+func (s *Seeker) startWarmup() {
+	w := &warmup{done: make(chan struct{})}
+	s.warm = w
+	ctx := s.streamContext()
+	go func() {
+		defer close(w.done)
+		w.r, w.size, w.resp, w.err = s.open(ctx, 0, s.streamLength(0))
+	}()
+}
+
+// takeWarmup waits for the warm-up and returns it if the stream is opened at offset 0.
+// Otherwise the warm-up is discarded and takeWarmup returns nil.
+func (s *Seeker) takeWarmup(offset int64) *warmup {
+	if s.warm == nil {
+		return nil
+	}
+	if offset != 0 {
+		s.closeWarmup()
+		return nil
+	}
+	w := s.warm
+	s.warm = nil
+	<-w.done
+	return w
+}
+
+// closeWarmup discards the warm-up, canceling it if it is still in flight and closing its body.
+func (s *Seeker) closeWarmup() {
+	w := s.warm
+	if w == nil {
+		return
+	}
+	s.warm = nil
+	select {
+	case <-w.done:
+	default:
+		s.cancelStream()
+		<-w.done
+	}
+	if w.r != nil {
+		_ = w.r.Close()
+	}
+}
@@ -0,0 +1,94 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWarmup(t *testing.T) {
+	ctx := context.Background()
+
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(100 * time.Millisecond)
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader([]byte("Hello World!")))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("read", func(t *testing.T) {
+		requests.Store(0)
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithWarmup())
+		defer rsc.Close()
+		time.Sleep(200 * time.Millisecond)
+
+		start := time.Now()
+		buf := make([]byte, 5)
+		if _, err := io.ReadFull(rsc, buf); err != nil {
+			t.Fatal(err)
+		}
+		if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+			t.Fatalf("first read took %s, want the warmed-up body", elapsed)
+		}
+		if string(buf) != "Hello" {
+			t.Fatalf("got %q, want %q", buf, "Hello")
+		}
+		if got := requests.Load(); got != 1 {
+			t.Fatalf("got %d requests, want 1", got)
+		}
+	})
+
+	t.Run("seek", func(t *testing.T) {
+		requests.Store(0)
+		rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithWarmup())
+		defer rsc.Close()
+
+		if _, err := rsc.Seek(6, io.SeekStart); err != nil {
+			t.Fatal(err)
+		}
+		got, err := io.ReadAll(rsc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != "World!" {
+			t.Fatalf("got %q, want %q", got, "World!")
+		}
+		if got := rsc.CurrentAttempt(); !got.Ranged || got.Offset != 6 {
+			t.Fatalf("got %+v, want the stream read from the ranged attempt", got)
+		}
+	})
+}
+
+func TestWarmupError(t *testing.T) {
+	ctx := context.Background()
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithWarmup(), WithStatusAction(http.StatusNotFound, StatusFail))
+	defer rsc.Close()
+
+	_, err = rsc.Read(make([]byte, 1))
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("got %v, want the warm-up status error", err)
+	}
+}
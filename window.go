@@ -0,0 +1,74 @@
+package httpseek
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// windowBody reads a range response that covers less than the requested range, as sent by servers
+// capping the size of a range response, and requests the rest of the range each time a window is exhausted,
+// so that the caller sees one continuous body.
+type windowBody struct {
+	s      *Seeker
+	ctx    context.Context
+	rc     io.ReadCloser
+	offset int64
+	// end is the offset after the last byte of the current window.
+	end int64
+	// length is the number of bytes requested from offset, or -1 for the rest of the content.
+	length int64
+}
+
+func (s *Seeker) newWindowBody(ctx context.Context, rc io.ReadCloser, offset, end int64, length int64) *windowBody {
+	return &windowBody{s: s, ctx: ctx, rc: rc, offset: offset, end: end, length: length}
+}
+
+func (w *windowBody) Read(p []byte) (int, error) {
+	n, err := w.rc.Read(p)
+	if n > 0 {
+		w.offset += int64(n)
+		if w.length >= 0 {
+			w.length -= int64(n)
+		}
+	}
+	if err != io.EOF || w.offset < w.end || w.length == 0 {
+		return n, err
+	}
+	if size := w.s.Size(); w.length < 0 && size >= 0 && w.offset >= size {
+		return n, io.EOF
+	}
+	if err := w.next(); err != nil {
+		return n, err
+	}
+	if n == 0 {
+		return w.Read(p)
+	}
+	return n, nil
+}
+
+// next requests the bytes after the exhausted window.
+func (w *windowBody) next() error {
+	_ = w.rc.Close()
+	w.rc = http.NoBody
+	r, size, resp, err := w.s.open(w.ctx, w.offset, w.length)
+	if err != nil {
+		return err
+	}
+	if resp != nil {
+		_ = r.Close()
+		return &StatusError{StatusCode: resp.StatusCode, Header: resp.Header}
+	}
+	w.end = requestedEnd(w.offset, w.length, size)
+	if next, ok := r.(*windowBody); ok {
+		r, w.end = next.rc, next.end
+	} else if r == http.NoBody {
+		return io.EOF
+	}
+	w.rc = r
+	return nil
+}
+
+func (w *windowBody) Close() error {
+	return w.rc.Close()
+}
@@ -0,0 +1,67 @@
+package httpseek
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestShortWindows(t *testing.T) {
+	ctx := context.Background()
+
+	const content = "Hello World!"
+	const window = 5
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var start, end int
+		end = len(content) - 1
+		if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-%d", &start, &end); err != nil {
+			if _, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start); err != nil {
+				http.Error(w, "only ranges are served", http.StatusBadRequest)
+				return
+			}
+		}
+		end = min(end, start+window-1)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		io.WriteString(w, content[start:end+1])
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeeker(ctx, s.Client().Transport, req)
+	defer rsc.Close()
+
+	if _, err := rsc.Seek(1, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != content[1:] {
+		t.Fatalf("got %q, want %q", got, content[1:])
+	}
+	if requests := rsc.Stats().Requests; requests != 3 {
+		t.Fatalf("got %d requests, want %d", requests, 3)
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rsc.ReadAt(buf, 2); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != content[2:10] {
+		t.Fatalf("got %q, want %q", buf, content[2:10])
+	}
+
+	buf = make([]byte, 8)
+	n, err := rsc.ReadAt(buf, 6)
+	if err != io.EOF || string(buf[:n]) != content[6:] {
+		t.Fatalf("got %q, %v, want %q, %v", buf[:n], err, content[6:], io.EOF)
+	}
+}
@@ -0,0 +1,46 @@
+package httpseek
+
+import (
+	"errors"
+	"io"
+)
+
+const copyBufferSize = 256 << 10
+
+// WriteTo writes the content from the current offset to the end into w, implementing io.WriterTo
+// so that io.Copy streams through a larger pooled buffer.
+// A body that breaks mid-copy is resumed at the current offset, allowing as many consecutive
+// failures without progress as the retry budget of WithRetry.
+// WriteTo keeps no checkpoint; see Download to resume a transfer across runs.
+func (s *Seeker) WriteTo(w io.Writer) (int64, error) {
+	bufp := getBuffer(copyBufferSize)
+	defer putBuffer(bufp)
+	buf := *bufp
+
+	var written int64
+	failures := 0
+	for {
+		n, err := s.Read(buf)
+		if n > 0 {
+			failures = 0
+			m, werr := w.Write(buf[:n])
+			written += int64(m)
+			if werr != nil {
+				return written, werr
+			}
+			if m != n {
+				return written, io.ErrShortWrite
+			}
+		}
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			var readErr *ReadError
+			if !errors.As(err, &readErr) || failures >= s.cfg.retries {
+				return written, err
+			}
+			failures++
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package httpseek
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWriteTo(t *testing.T) {
+	ctx := context.Background()
+
+	content := make([]byte, 1<<20)
+	rand.New(rand.NewSource(1)).Read(content)
+	var requests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Content-Length", "1048576")
+			w.Write(content[:300<<10])
+			return
+		}
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsc := NewSeekerWithOptions(ctx, s.Client().Transport, req, WithRetry(2, time.Millisecond))
+	defer rsc.Close()
+
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, rsc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(len(content)) || !bytes.Equal(buf.Bytes(), content) {
+		t.Fatalf("got %d bytes, want the %d bytes of the content", n, len(content))
+	}
+	if rsc.Offset() != int64(len(content)) {
+		t.Fatalf("got offset %d, want %d", rsc.Offset(), len(content))
+	}
+	if requests.Load() != 2 {
+		t.Fatalf("got %d requests, want the broken one and its resume", requests.Load())
+	}
+	if !rsc.IsComplete() {
+		t.Fatal("expected the transfer to be complete")
+	}
+}
+
+func BenchmarkCopy(b *testing.B) {
+	ctx := context.Background()
+
+	content := make([]byte, 16<<20)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "test", time.Time{}, bytes.NewReader(content))
+	}))
+	defer s.Close()
+
+	run := func(b *testing.B, hideWriteTo bool) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(content)))
+		for i := 0; i < b.N; i++ {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			rsc := NewSeeker(ctx, s.Client().Transport, req)
+			var src io.Reader = rsc
+			if hideWriteTo {
+				src = struct{ io.Reader }{rsc}
+			}
+			// Hide io.Discard's ReaderFrom so that io.Copy picks the source's path.
+			if _, err := io.Copy(struct{ io.Writer }{io.Discard}, src); err != nil {
+				b.Fatal(err)
+			}
+			rsc.Close()
+		}
+	}
+	b.Run("WriteTo", func(b *testing.B) { run(b, false) })
+	b.Run("Read", func(b *testing.B) { run(b, true) })
+}